@@ -3,3 +3,5 @@ module github.com/soprinter/go-sharenote
 go 1.22
 
 retract v0.1.0 // Retired version.
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -1,13 +1,23 @@
 package snip00
 
 import (
+	"cmp"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"math"
 	"math/big"
+	"math/rand/v2"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 const (
@@ -17,6 +27,13 @@ const (
 	centZUnitsPerZ = int(1 / CentZBitStep)
 	MinCentZ       = 0
 	MaxCentZ       = 99
+	// maxLabelZ bounds the whole-Z component of every note so zbits convert to (Z, cents) without
+	// overflow and centZ() fits in a 32-bit int.
+	maxLabelZ = math.MaxInt32/centZUnitsPerZ - 1
+	// closedUpperEpsilon is the relative pull-back applied to an inclusive range upper bound.
+	closedUpperEpsilon = 1e-9
+	// maxAchievableNotes caps AchievableNotes enumeration at 256 whole Z.
+	maxAchievableNotes = 256 * centZUnitsPerZ
 )
 
 // ReliabilityID enumerates the supported reliability presets.
@@ -38,6 +55,14 @@ const (
 	PrimaryModeQuantile PrimaryMode = "quantile"
 )
 
+// RoundingMode selects how derived Z-bit values snap onto cent-Z labels.
+type RoundingMode string
+
+const (
+	RoundingFloor   RoundingMode = "floor"
+	RoundingNearest RoundingMode = "nearest"
+)
+
 // HashrateUnit represents canonical hashrate units.
 type HashrateUnit string
 
@@ -102,6 +127,30 @@ func (h HashrateMeasurement) String() string {
 	return h.Human().String()
 }
 
+// Compare returns -1, 0, or 1 ordering the receiver against other; NaN orders after every number.
+func (h HashrateMeasurement) Compare(other HashrateMeasurement) int {
+	aNaN, bNaN := math.IsNaN(h.Value), math.IsNaN(other.Value)
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return 1
+	case bNaN:
+		return -1
+	case h.Value < other.Value:
+		return -1
+	case h.Value > other.Value:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SortHashrates sorts measurements ascending in place, placing NaN values last.
+func SortHashrates(ms []HashrateMeasurement) {
+	slices.SortStableFunc(ms, HashrateMeasurement.Compare)
+}
+
 // HashesMeasurement exposes an expected hash count with helper methods.
 type HashesMeasurement struct {
 	Value float64
@@ -112,10 +161,29 @@ func (h HashesMeasurement) Float64() float64 {
 	return h.Value
 }
 
+// HumanHashes represents an SI-prefixed hash count plus metadata.
+type HumanHashes struct {
+	Value    float64
+	Prefix   string
+	Display  string
+	Exponent int
+}
+
+// String implements fmt.Stringer by returning the precomputed display value.
+func (h HumanHashes) String() string {
+	return h.Display
+}
+
 // String returns a scientific-notation summary for the expected hashes.
 func (h HashesMeasurement) String() string {
+	return h.Human().Display
+}
+
+// Human returns the expected hash count scaled to an SI prefix with structured metadata.
+func (h HashesMeasurement) Human() HumanHashes {
+	zero := HumanHashes{Display: "0 hashes"}
 	if !isFinite(h.Value) || h.Value <= 0 {
-		return "0 hashes"
+		return zero
 	}
 	index := 0
 	if h.Value > 0 {
@@ -130,30 +198,78 @@ func (h HashesMeasurement) String() string {
 	unit := hashCountUnits[index]
 	scaled := h.Value / math.Pow(1000, float64(unit.exponent))
 	if !isFinite(scaled) || scaled <= 0 {
-		return "0 hashes"
+		return zero
+	}
+
+	numeric := formatScaledCount(scaled)
+	unitLabel := fmt.Sprintf("%sH/s", unit.prefix)
+	if unit.prefix == "" {
+		unitLabel = "H/s"
+	}
+	return HumanHashes{
+		Value:    scaled,
+		Prefix:   unit.prefix,
+		Display:  fmt.Sprintf("%s %s", numeric, unitLabel),
+		Exponent: unit.exponent,
 	}
+}
 
-	var numeric string
+// formatScaledCount renders a unit-scaled count to roughly three significant figures.
+func formatScaledCount(scaled float64) string {
 	switch {
 	case scaled >= 100:
-		numeric = fmt.Sprintf("%.0f", scaled)
+		return fmt.Sprintf("%.0f", scaled)
 	case scaled >= 10:
-		numeric = fmt.Sprintf("%.1f", scaled)
+		return fmt.Sprintf("%.1f", scaled)
 	default:
-		numeric = fmt.Sprintf("%.2f", scaled)
+		return fmt.Sprintf("%.2f", scaled)
 	}
+}
 
-	unitLabel := fmt.Sprintf("%sH/s", unit.prefix)
-	if unit.prefix == "" {
-		unitLabel = "H/s"
+// OddsString renders the per-hash probability as "1 in N" with N in words, e.g. "1 in 12.4 billion".
+func (n Sharenote) OddsString() (string, error) {
+	expected, err := ExpectedHashesForZBits(n.ZBits)
+	if err != nil {
+		return "", err
+	}
+	if !isFinite(expected.Value) {
+		return "", errors.New("expected hashes overflow")
 	}
-	return fmt.Sprintf("%s %s", numeric, unitLabel)
+	human := expected.Human()
+	if human.Exponent == 0 {
+		return "1 in " + strconv.FormatFloat(math.Round(expected.Value), 'f', -1, 64), nil
+	}
+	return fmt.Sprintf("1 in %s %s", formatScaledCount(human.Value), hashCountWords[human.Exponent]), nil
 }
 
 // HashrateRange represents the inclusive/exclusive H/s interval that maps to a Sharenote label.
 type HashrateRange struct {
-	Min float64
-	Max float64
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// MarshalJSON encodes min and max alongside their humanised displays as min_human and max_human.
+func (r HashrateRange) MarshalJSON() ([]byte, error) {
+	low, high := r.Human()
+	return json.Marshal(struct {
+		Min      float64 `json:"min"`
+		Max      float64 `json:"max"`
+		MinHuman string  `json:"min_human"`
+		MaxHuman string  `json:"max_human"`
+	}{r.Min, r.Max, low.Display, high.Display})
+}
+
+// UnmarshalJSON reads min and max; the humanised fields are derived data and ignored.
+func (r *HashrateRange) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = HashrateRange{Min: raw.Min, Max: raw.Max}
+	return nil
 }
 
 // Human renders the min/max bounds into human-readable units.
@@ -161,6 +277,16 @@ func (r HashrateRange) Human(opts ...HumanHashrateOption) (HumanHashrate, HumanH
 	return HumaniseHashrate(r.Min, opts...), HumaniseHashrate(r.Max, opts...)
 }
 
+// String implements fmt.Stringer using half-open notation (e.g. "[1.00 GH/s, 5.00 GH/s)").
+// A degenerate range where Min equals Max renders as a single value, e.g. "[1.00 GH/s]".
+func (r HashrateRange) String() string {
+	low, high := r.Human()
+	if r.Min == r.Max {
+		return fmt.Sprintf("[%s]", low)
+	}
+	return fmt.Sprintf("[%s, %s)", low, high)
+}
+
 // String implements fmt.Stringer and favours the precomputed display value.
 func (h HumanHashrate) String() string {
 	if h.Display != "" {
@@ -178,20 +304,30 @@ func (h HumanHashrate) String() string {
 
 // BillEstimate summarises the metrics required to mint a note within a time window.
 type BillEstimate struct {
-	Sharenote                Sharenote
-	Label                    string
-	ZBits                    float64
-	SecondsTarget            float64
-	ProbabilityPerHash       float64
-	ProbabilityDisplay       string
-	ExpectedHashes           float64
-	RequiredHashrateMean     float64
-	RequiredHashrateQuantile float64
-	RequiredHashratePrimary  float64
-	RequiredHashrateHuman    HumanHashrate
-	Multiplier               float64
-	Quantile                 *float64
-	PrimaryMode              PrimaryMode
+	Sharenote                     Sharenote
+	Label                         string
+	ZBits                         float64
+	SecondsTarget                 float64
+	EffectiveSeconds              float64
+	ProbabilityPerHash            float64
+	ProbabilityDisplay            string
+	ExpectedHashes                float64
+	RequiredHashrateMean          float64
+	RequiredHashrateQuantile      float64
+	RequiredHashratePrimary       float64
+	RequiredHashrateHuman         HumanHashrate
+	RequiredHashrateMeanHuman     HumanHashrate
+	RequiredHashrateQuantileHuman HumanHashrate
+	Multiplier                    float64
+	Quantile                      *float64
+	PrimaryMode                   PrimaryMode
+	// ReliabilityLabel names the reliability level nearest to Multiplier, e.g. "Often (95%)".
+	ReliabilityLabel string
+	// Feasible and Shortfall are only populated when WithEstimateRigHashrate is supplied.
+	Feasible  bool
+	Shortfall float64
+	// Annotations carries caller metadata supplied via WithEstimateAnnotations.
+	Annotations map[string]string `json:",omitempty"`
 }
 
 // String implements fmt.Stringer with a compact summary for logging.
@@ -210,6 +346,40 @@ func (b BillEstimate) String() string {
 	)
 }
 
+// Summary renders the estimate as a sentence, e.g. "Minting 33Z53 in 5s needs ~7.43 GH/s (95% confidence)."
+func (b BillEstimate) Summary() string {
+	qualifier := "on average"
+	if b.PrimaryMode == PrimaryModeQuantile && b.Quantile != nil {
+		percent := math.Round(*b.Quantile*1e4) / 100
+		qualifier = strconv.FormatFloat(percent, 'f', -1, 64) + "% confidence"
+	}
+	// The hashrate was computed over the effective (possibly clamped) window, so report that one.
+	seconds := b.EffectiveSeconds
+	if seconds <= 0 {
+		seconds = b.SecondsTarget
+	}
+	return fmt.Sprintf(
+		"Minting %s in %ss needs ~%s (%s).",
+		b.Label,
+		strconv.FormatFloat(seconds, 'f', -1, 64),
+		b.RequiredHashrateHuman.Display,
+		qualifier,
+	)
+}
+
+// MeetsHashrate reports whether the provided H/s satisfies the primary requirement.
+func (b BillEstimate) MeetsHashrate(hashrate float64) bool {
+	return hashrate >= b.RequiredHashratePrimary
+}
+
+// HashrateShortfall returns the additional H/s needed to meet the primary requirement (0 if met).
+func (b BillEstimate) HashrateShortfall(hashrate float64) float64 {
+	if b.MeetsHashrate(hashrate) {
+		return 0
+	}
+	return b.RequiredHashratePrimary - hashrate
+}
+
 // SharenotePlan summarises a computed note and its supporting bill estimate for a given rig.
 type SharenotePlan struct {
 	Sharenote          Sharenote
@@ -217,6 +387,28 @@ type SharenotePlan struct {
 	SecondsTarget      float64
 	InputHashrateHPS   float64
 	InputHashrateHuman HumanHashrate
+	HashrateMultiplier float64
+	Rounding           RoundingMode
+}
+
+// Verify checks that InputHashrateHPS falls within the hashrate range of the plan's label at the
+// multiplier and rounding used to derive it, guarding against drift between NoteFromHashrate and
+// HashrateRangeForNote.
+func (p SharenotePlan) Verify() error {
+	rng, err := HashrateRangeForNote(p.Sharenote.Quantize(), p.SecondsTarget, WithMultiplier(p.HashrateMultiplier))
+	if err != nil {
+		return err
+	}
+	if p.Rounding == RoundingNearest {
+		shift := math.Exp2(-CentZBitStep / 2)
+		rng.Min *= shift
+		rng.Max *= shift
+	}
+	const slack = 1e-9
+	if p.InputHashrateHPS < rng.Min*(1-slack) || p.InputHashrateHPS >= rng.Max*(1+slack) {
+		return fmt.Errorf("input hashrate %s outside %s for %s", HumaniseHashrate(p.InputHashrateHPS), rng, p.Sharenote.Label())
+	}
+	return nil
 }
 
 // String implements fmt.Stringer for concise plan inspection.
@@ -229,6 +421,11 @@ func (p SharenotePlan) String() string {
 	)
 }
 
+// String implements fmt.Stringer, e.g. "often_95 (Often (95%), ×2.996)".
+func (l ReliabilityLevel) String() string {
+	return fmt.Sprintf("%s (%s, ×%.3f)", l.ID, l.Label, l.Multiplier)
+}
+
 // Label returns the canonical Sharenote label (e.g. "33Z53").
 func (n Sharenote) Label() string {
 	if n.labelOverride != "" {
@@ -257,16 +454,43 @@ func (n Sharenote) RequiredHashrate(seconds float64, opts ...HashrateOption) (Ha
 	return RequiredHashrate(n, seconds, opts...)
 }
 
+// RequiredHashrateDefault is RequiredHashrate over the window configured with SetDefaultWindow.
+func (n Sharenote) RequiredHashrateDefault(opts ...HashrateOption) (HashrateMeasurement, error) {
+	seconds, err := defaultWindowSeconds()
+	if err != nil {
+		return HashrateMeasurement{}, err
+	}
+	return RequiredHashrate(n, seconds, opts...)
+}
+
 // RequiredHashrateMean returns the mean H/s requirement for the receiver.
 func (n Sharenote) RequiredHashrateMean(seconds float64) (HashrateMeasurement, error) {
 	return RequiredHashrateMean(n, seconds)
 }
 
+// RequiredHashrateMeanDefault is RequiredHashrateMean over the window configured with SetDefaultWindow.
+func (n Sharenote) RequiredHashrateMeanDefault() (HashrateMeasurement, error) {
+	seconds, err := defaultWindowSeconds()
+	if err != nil {
+		return HashrateMeasurement{}, err
+	}
+	return RequiredHashrateMean(n, seconds)
+}
+
 // RequiredHashrateQuantile returns the quantile H/s requirement for the receiver.
 func (n Sharenote) RequiredHashrateQuantile(seconds, confidence float64) (HashrateMeasurement, error) {
 	return RequiredHashrateQuantile(n, seconds, confidence)
 }
 
+// RequiredHashrateQuantileDefault is RequiredHashrateQuantile over the window configured with SetDefaultWindow.
+func (n Sharenote) RequiredHashrateQuantileDefault(confidence float64) (HashrateMeasurement, error) {
+	seconds, err := defaultWindowSeconds()
+	if err != nil {
+		return HashrateMeasurement{}, err
+	}
+	return RequiredHashrateQuantile(n, seconds, confidence)
+}
+
 // RequiredHashrateMeasurement returns a measurement struct for the required H/s.
 func (n Sharenote) RequiredHashrateMeasurement(seconds float64, opts ...HashrateOption) (HashrateMeasurement, error) {
 	return RequiredHashrateMeasurement(n, seconds, opts...)
@@ -287,6 +511,24 @@ func (n Sharenote) HashrateRange(seconds float64, opts ...HashrateOption) (Hashr
 	return HashrateRangeForNote(n, seconds, opts...)
 }
 
+// HashrateRangeDefault is HashrateRange over the window configured with SetDefaultWindow.
+func (n Sharenote) HashrateRangeDefault(opts ...HashrateOption) (HashrateRange, error) {
+	seconds, err := defaultWindowSeconds()
+	if err != nil {
+		return HashrateRange{}, err
+	}
+	return HashrateRangeForNote(n, seconds, opts...)
+}
+
+// EstimateDefault is EstimateNote for the receiver over the window configured with SetDefaultWindow.
+func (n Sharenote) EstimateDefault(opts ...EstimateOption) (BillEstimate, error) {
+	seconds, err := defaultWindowSeconds()
+	if err != nil {
+		return BillEstimate{}, err
+	}
+	return EstimateNote(n, seconds, opts...)
+}
+
 // Target returns the integer hash target for the receiver.
 func (n Sharenote) Target() (*big.Int, error) {
 	return TargetFor(n)
@@ -315,6 +557,93 @@ func (n Sharenote) NBits() (string, error) {
 	return SharenoteToNBits(n)
 }
 
+// Quantize returns a copy of the receiver with ZBits snapped down onto the cent-Z grid implied by its label.
+func (n Sharenote) Quantize() Sharenote {
+	n.ZBits = float64(n.Z) + float64(clampCents(n.Cents))*CentZBitStep
+	return n
+}
+
+// Frac returns the sub-cent-Z remainder of ZBits beyond the receiver's label (≈0 for grid-aligned notes).
+func (n Sharenote) Frac() float64 {
+	return n.ZBits - n.Quantize().ZBits
+}
+
+// Equal reports whether two notes describe the same difficulty: Z, Cents and ZBits match.
+// Label overrides are ignored; use EqualStrict to compare them too.
+func (n Sharenote) Equal(other Sharenote) bool {
+	return n.Z == other.Z && n.Cents == other.Cents && n.ZBits == other.ZBits
+}
+
+// EqualStrict is Equal plus a matching label override, equivalent to ==.
+func (n Sharenote) EqualStrict(other Sharenote) bool {
+	return n.Equal(other) && n.labelOverride == other.labelOverride
+}
+
+// WithZBits returns a note at the given zbits that keeps the receiver's label override, if any.
+func (n Sharenote) WithZBits(zbits float64) (Sharenote, error) {
+	note, err := NoteFromZBits(zbits)
+	if err != nil {
+		return Sharenote{}, err
+	}
+	note.labelOverride = n.labelOverride
+	return note, nil
+}
+
+// CentZDecimal returns the receiver's label in the spreadsheet shorthand, e.g. 33.53 for 33Z53.
+// See NoteFromCentZDecimal; the result is not the note's zbits.
+func (n Sharenote) CentZDecimal() float64 {
+	return float64(n.centZ()) / float64(centZUnitsPerZ)
+}
+
+// Next returns the canonical note one cent-Z harder than the receiver's label, carrying into Z as needed.
+// Like Prev, it errors when there is no such note (the Z component would overflow).
+func (n Sharenote) Next() (Sharenote, error) {
+	z, cents := n.Z, clampCents(n.Cents)+1
+	if cents > MaxCentZ {
+		if z == math.MaxInt {
+			return Sharenote{}, errors.New("z too large; no next note")
+		}
+		z, cents = z+1, MinCentZ
+	}
+	return noteFromComponents(z, cents)
+}
+
+// Prev returns the canonical note one cent-Z easier than the receiver's label; 0Z00 has no predecessor.
+func (n Sharenote) Prev() (Sharenote, error) {
+	if n.centZ() <= 0 {
+		return Sharenote{}, errors.New("0Z00 has no previous note")
+	}
+	return NoteFromCentZBits(n.centZ() - 1)
+}
+
+// IsHarderThan reports whether the receiver's label is rarer than other's (see CompareNotes).
+func (n Sharenote) IsHarderThan(other any) (bool, error) {
+	cmp, err := CompareNotes(n, other)
+	return cmp > 0, err
+}
+
+// IsEasierThan reports whether the receiver's label is more common than other's (see CompareNotes).
+func (n Sharenote) IsEasierThan(other any) (bool, error) {
+	cmp, err := CompareNotes(n, other)
+	return cmp < 0, err
+}
+
+// EqualsNote reports whether the receiver's ZBits lie within tolZBits of other's.
+func (n Sharenote) EqualsNote(other any, tolZBits float64) (bool, error) {
+	if !isFinite(tolZBits) || tolZBits < 0 {
+		return false, errors.New("tolerance must be >= 0")
+	}
+	resolved, err := EnsureNote(other)
+	if err != nil {
+		return false, err
+	}
+	return math.Abs(n.ZBits-resolved.ZBits) <= tolZBits, nil
+}
+
+func (n Sharenote) centZ() int {
+	return n.Z*centZUnitsPerZ + clampCents(n.Cents)
+}
+
 var reliabilityLevels = map[ReliabilityID]ReliabilityLevel{
 	ReliabilityMean: {
 		ID:         ReliabilityMean,
@@ -362,6 +691,9 @@ var hashrateUnits = []struct {
 	{HashrateUnitZHps, 7},
 }
 
+// hashCountWords names the power-of-1000 exponents of hashCountUnits for prose displays.
+var hashCountWords = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion", "sextillion", "septillion"}
+
 var hashCountUnits = []struct {
 	prefix   string
 	exponent int
@@ -378,13 +710,54 @@ var hashCountUnits = []struct {
 }
 
 var (
-	reDecimal             = regexp.MustCompile(`^(\d+(?:\.\d+)?)Z$`)
-	reStandard            = regexp.MustCompile(`^(\d+)Z(?:(\d{1,2})(?:CZ)?)?$`)
-	reDotted              = regexp.MustCompile(`^(\d+)\.(\d{1,2})Z$`)
-	hashrateStringPattern = regexp.MustCompile(`^([+-]?(?:\d+(?:[_,]?\d+)*(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)\s*([A-Za-z\/\s-]+)?$`)
-	hashrateUnitPattern   = regexp.MustCompile(`^([KMGTPEZ]?)(H)/S$`)
+	reDecimal               = regexp.MustCompile(`^(\d+(?:\.\d+)?(?:E[+-]?\d+)?)Z$`)
+	reStandard              = regexp.MustCompile(`^(\d+)Z(?:(\d{1,2})(?:CZ)?)?$`)
+	reDotted                = regexp.MustCompile(`^(\d+)\.(\d{1,2})Z$`)
+	hashrateStringPattern   = regexp.MustCompile(`^([+-]?(?:\d+(?:[_,]?\d+)*(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)\s*([A-Za-z\/\s-]+)?$`)
+	hashrateUnitPattern     = regexp.MustCompile(`^([KMGTPEZ]?)(H)/S$`)
+	difficultyStringPattern = regexp.MustCompile(`^((?:\d+(?:[_,]?\d+)*(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)\s*([KkMGTP]?)$`)
+	bitsStringPattern       = regexp.MustCompile(`^((?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)\s*(?:(?i:bits?|b))?$`)
+	durationStringPattern   = regexp.MustCompile(`^((?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)\s*([A-Za-z]+)$`)
+)
+
+var difficultyPrefixes = []string{"", "K", "M", "G", "T", "P"}
+
+var solutionUnitReplacer = strings.NewReplacer(
+	"SOLUTIONS", "H",
+	"SOLUTION", "H",
+	"SOLS", "H",
+	"SOL", "H",
 )
 
+var durationUnitSeconds = map[string]float64{
+	"ms":           1e-3,
+	"msec":         1e-3,
+	"millisecond":  1e-3,
+	"milliseconds": 1e-3,
+	"s":            1,
+	"sec":          1,
+	"secs":         1,
+	"second":       1,
+	"seconds":      1,
+	"min":          60,
+	"mins":         60,
+	"minute":       60,
+	"minutes":      60,
+	"h":            3600,
+	"hr":           3600,
+	"hrs":          3600,
+	"hour":         3600,
+	"hours":        3600,
+	"d":            86400,
+	"day":          86400,
+	"days":         86400,
+	"y":            31_557_600,
+	"yr":           31_557_600,
+	"yrs":          31_557_600,
+	"year":         31_557_600,
+	"years":        31_557_600,
+}
+
 var hashratePrefixExponent = map[string]int{
 	"":  0,
 	"K": 1,
@@ -407,13 +780,117 @@ var prefixToUnit = map[string]HashrateUnit{
 	"Z": HashrateUnitZHps,
 }
 
+var (
+	defaultsMu         sync.RWMutex
+	defaultReliability = ReliabilityMean
+	defaultWindow      float64
+)
+
+// SetDefaultWindow sets the window, in seconds, used by the Sharenote *Default variants
+// (RequiredHashrateDefault, HashrateRangeDefault, EstimateDefault, ...). Pass 0 to clear it;
+// those variants then return an error. Negative or non-finite seconds are rejected.
+func SetDefaultWindow(seconds float64) error {
+	if !isFinite(seconds) || seconds < 0 {
+		return errors.New("seconds must be >= 0")
+	}
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultWindow = seconds
+	return nil
+}
+
+func defaultWindowSeconds() (float64, error) {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	if defaultWindow <= 0 {
+		return 0, errors.New("no default window set; call SetDefaultWindow")
+	}
+	return defaultWindow, nil
+}
+
+// SetDefaultReliability selects the preset applied by EstimateNote and RequiredHashrate when no
+// multiplier, confidence, or reliability option is supplied. Explicit options always win, and other
+// helpers keep their mean default. Pass ReliabilityMean to restore the built-in behaviour.
+// Unknown IDs are rejected and leave the current default in place.
+func SetDefaultReliability(id ReliabilityID) error {
+	if _, err := getReliabilityLevel(id); err != nil {
+		return err
+	}
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultReliability = id
+	return nil
+}
+
+func defaultReliabilityLevel() ReliabilityLevel {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	lvl, _ := getReliabilityLevel(defaultReliability)
+	return lvl
+}
+
 func getReliabilityLevel(id ReliabilityID) (ReliabilityLevel, error) {
 	if lvl, ok := reliabilityLevels[id]; ok {
 		return lvl, nil
 	}
+	customReliabilityMu.RLock()
+	defer customReliabilityMu.RUnlock()
+	for _, lvl := range customReliabilityLevels {
+		if lvl.ID == id {
+			return lvl, nil
+		}
+	}
 	return ReliabilityLevel{}, fmt.Errorf("unknown reliability level: %s", id)
 }
 
+var builtinReliabilityOrder = []ReliabilityID{
+	ReliabilityMean,
+	ReliabilityUsually90,
+	ReliabilityOften95,
+	ReliabilityVeryLikely99,
+	ReliabilityAlmost999,
+}
+
+// customReliabilityLevels is copy-on-write: RegisterReliabilityLevel swaps in a new slice rather than
+// mutating the old one, so a slice read under customReliabilityMu stays a consistent snapshot.
+var (
+	customReliabilityMu     sync.RWMutex
+	customReliabilityLevels []ReliabilityLevel
+)
+
+// RegisterReliabilityLevel adds a custom preset usable anywhere a ReliabilityID is accepted.
+// The ID must be new, the multiplier finite and > 0, and any confidence within (0,1).
+func RegisterReliabilityLevel(level ReliabilityLevel) error {
+	if level.ID == "" {
+		return errors.New("reliability id must not be empty")
+	}
+	if !isFinite(level.Multiplier) || level.Multiplier <= 0 {
+		return errors.New("multiplier must be > 0")
+	}
+	if level.Confidence != nil {
+		if c := *level.Confidence; !isFinite(c) || c <= 0 || c >= 1 {
+			return errors.New("confidence must be in (0,1)")
+		}
+		val := *level.Confidence
+		level.Confidence = &val
+	}
+	customReliabilityMu.Lock()
+	defer customReliabilityMu.Unlock()
+	_, builtin := reliabilityLevels[level.ID]
+	if builtin || slices.ContainsFunc(customReliabilityLevels, func(lvl ReliabilityLevel) bool { return lvl.ID == level.ID }) {
+		return fmt.Errorf("reliability level already registered: %s", level.ID)
+	}
+	levels := append(slices.Clip(customReliabilityLevels), level)
+	slices.SortStableFunc(levels, func(a, b ReliabilityLevel) int {
+		if c := cmp.Compare(a.Multiplier, b.Multiplier); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	customReliabilityLevels = levels
+	return nil
+}
+
 func normalizeHashrateUnitString(raw string) string {
 	normalized := strings.ToUpper(strings.TrimSpace(raw))
 	replacer := strings.NewReplacer(
@@ -422,6 +899,8 @@ func normalizeHashrateUnitString(raw string) string {
 		" ", "",
 	)
 	normalized = replacer.Replace(normalized)
+	normalized = strings.ReplaceAll(normalized, "/SECOND", "/S")
+	normalized = strings.ReplaceAll(normalized, "/SEC", "/S")
 	normalized = strings.ReplaceAll(normalized, "HPS", "H/S")
 	normalized = strings.ReplaceAll(normalized, "HS", "H/S")
 	if !strings.HasSuffix(normalized, "/S") && strings.Contains(normalized, "H") {
@@ -469,30 +948,52 @@ func NormalizeHashrateValue(value HashrateValue) (float64, error) {
 	return value.Value * math.Pow(10, float64(exponent*3)), nil
 }
 
-// ParseHashrate accepts human-readable strings (e.g. "5 GH/s") and returns H/s.
-func ParseHashrate(input string) (float64, error) {
-	trimmed := strings.TrimSpace(input)
-	if trimmed == "" {
-		return 0, errors.New("hashrate string must not be empty")
-	}
-	match := hashrateStringPattern.FindStringSubmatch(trimmed)
-	if match == nil {
-		return 0, fmt.Errorf("unrecognised hashrate format: %q", input)
+// AddHashrateValues sums hashrates given in any units and returns the total in an auto-selected unit.
+func AddHashrateValues(values ...HashrateValue) (HashrateValue, error) {
+	if len(values) == 0 {
+		return HashrateValue{}, errors.New("values slice must not be empty")
 	}
-	magnitudeStr := strings.NewReplacer("_", "", ",", "").Replace(match[1])
-	value, err := strconv.ParseFloat(magnitudeStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("parse hashrate magnitude: %w", err)
+	total := 0.0
+	for i, value := range values {
+		hps, err := NormalizeHashrateValue(value)
+		if err != nil {
+			return HashrateValue{}, fmt.Errorf("value %d: %w", i, err)
+		}
+		total += hps
 	}
-	if !isFinite(value) {
-		return 0, errors.New("hashrate magnitude must be finite")
+	if !isFinite(total) {
+		return HashrateValue{}, errors.New("hashrate sum overflow")
 	}
-	if value < 0 {
+	human := HumaniseHashrate(total)
+	return HashrateValue{Value: human.Value, Unit: human.Unit}, nil
+}
+
+// RoundHashrateUp rounds hashrate (H/s) up to the next multiple of stepInUnit expressed in unit,
+// e.g. 7.43e9 with a 0.5 GH/s step gives 7.5e9. Values already on a multiple are returned unchanged.
+func RoundHashrateUp(hashrate float64, stepInUnit float64, unit HashrateUnit) (float64, error) {
+	if !isFinite(hashrate) || hashrate < 0 {
 		return 0, errors.New("hashrate must be >= 0")
 	}
-	unitRaw := ""
-	if len(match) > 2 {
-		unitRaw = strings.TrimSpace(match[2])
+	if !isFinite(stepInUnit) || stepInUnit <= 0 {
+		return 0, errors.New("step must be > 0")
+	}
+	step, err := NormalizeHashrateValue(HashrateValue{Value: stepInUnit, Unit: unit})
+	if err != nil {
+		return 0, err
+	}
+	steps := hashrate / step
+	// Snap values within float error of a multiple so they are not pushed up a whole step.
+	if nearest := math.Round(steps); math.Abs(steps-nearest) <= 1e-9*steps {
+		return nearest * step, nil
+	}
+	return math.Ceil(steps) * step, nil
+}
+
+// ParseHashrate accepts human-readable strings (e.g. "5 GH/s") and returns H/s.
+func ParseHashrate(input string) (float64, error) {
+	value, unitRaw, err := splitHashrateString(input)
+	if err != nil {
+		return 0, err
 	}
 	exponent, _, err := resolveHashrateUnit(unitRaw)
 	if err != nil {
@@ -501,80 +1002,359 @@ func ParseHashrate(input string) (float64, error) {
 	return value * math.Pow(10, float64(exponent*3)), nil
 }
 
-// parseLabel converts textual labels (33Z53, 33.53Z, 33Z 53CZ) into a Sharenote.
-func parseLabel(label string) (Sharenote, error) {
-	cleaned := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(label), " ", ""))
-
-	if match := reStandard.FindStringSubmatch(cleaned); match != nil {
-		z, _ := strconv.Atoi(match[1])
-		cents := 0
-		if match[2] != "" {
-			cents, _ = strconv.Atoi(match[2])
-		}
-		return noteFromComponents(z, cents)
+// ParseHashrateLenient parses like ParseHashrate but treats an unrecognised unit as H/s, reporting
+// unitRecognized=false instead of failing. Only a malformed magnitude returns an error.
+func ParseHashrateLenient(input string) (float64, bool, error) {
+	value, unitRaw, err := splitHashrateString(input)
+	if err != nil {
+		return 0, false, err
 	}
-
-	if match := reDotted.FindStringSubmatch(cleaned); match != nil {
-		z, _ := strconv.Atoi(match[1])
-		decimals := match[2]
-		if len(decimals) < 2 {
-			decimals = decimals + strings.Repeat("0", 2-len(decimals))
-		} else if len(decimals) > 2 {
-			decimals = decimals[:2]
-		}
-		cents, _ := strconv.Atoi(decimals)
-		return noteFromComponents(z, cents)
+	exponent, _, err := resolveHashrateUnit(unitRaw)
+	if err != nil {
+		return value, false, nil
 	}
+	return value * math.Pow(10, float64(exponent*3)), true, nil
+}
 
-	if match := reDecimal.FindStringSubmatch(cleaned); match != nil {
-		zbits, err := strconv.ParseFloat(match[1], 64)
-		if err != nil {
-			return Sharenote{}, fmt.Errorf("parse zbits: %w", err)
-		}
-		return NoteFromZBits(zbits)
+// ParseHashrateValue parses a hashrate string like ParseHashrate but keeps the magnitude in its
+// canonical unit (e.g. "5 gh/s" -> {5, GH/s}); a missing unit yields H/s.
+func ParseHashrateValue(input string) (HashrateValue, error) {
+	value, unitRaw, err := splitHashrateString(input)
+	if err != nil {
+		return HashrateValue{}, err
 	}
-
-	return Sharenote{}, fmt.Errorf("unrecognised Sharenote label %q", label)
+	_, unit, err := resolveHashrateUnit(unitRaw)
+	if err != nil {
+		return HashrateValue{}, err
+	}
+	return HashrateValue{Value: value, Unit: unit}, nil
 }
 
-// noteFromComponents normalises (Z, cents) into a Sharenote struct using cent-Z precision.
-func noteFromComponents(z, cents int) (Sharenote, error) {
-	if z < 0 {
-		return Sharenote{}, errors.New("z must be non-negative")
+// MarshalText implements encoding.TextMarshaler as "<value> <unit>" (e.g. "5 GH/s"), printing the
+// magnitude exactly so UnmarshalText restores the same value.
+func (v HashrateValue) MarshalText() ([]byte, error) {
+	if _, err := NormalizeHashrateValue(v); err != nil {
+		return nil, err
 	}
-	c := clampCents(cents)
-	zbits := float64(z) + float64(c)*CentZBitStep
-	return Sharenote{Z: z, Cents: c, ZBits: zbits}, nil
+	unit := v.Unit
+	if unit == "" {
+		unit = HashrateUnitHps
+	}
+	return []byte(strconv.FormatFloat(v.Value, 'g', -1, 64) + " " + string(unit)), nil
 }
 
-// NoteFromComponents constructs a canonical Sharenote from integer Z and cent-Z precision.
-// It preserves the legacy cent-Z precision behaviour for callers that rely on (Z, CZ) inputs.
-func NoteFromComponents(z, cents int) (Sharenote, error) {
-	return noteFromComponents(z, cents)
+// UnmarshalText implements encoding.TextUnmarshaler via ParseHashrateValue.
+func (v *HashrateValue) UnmarshalText(text []byte) error {
+	parsed, err := ParseHashrateValue(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
 }
 
-func labelComponentsFromZBits(zbits float64) (int, int) {
-	z := int(math.Floor(zbits))
-	if z < 0 {
-		z = 0
+// ParseHashrateStrict is ParseHashrate but rejects input without an explicit unit (e.g. a bare "5").
+func ParseHashrateStrict(input string) (float64, error) {
+	_, unitRaw, err := splitHashrateString(input)
+	if err != nil {
+		return 0, err
 	}
-	fractional := zbits - float64(z)
-	rawCents := int(math.Floor((fractional / CentZBitStep) + 1e-9))
-	return z, clampCents(rawCents)
+	if unitRaw == "" {
+		return 0, fmt.Errorf("hashrate %q is missing a unit", input)
+	}
+	return ParseHashrate(input)
 }
 
-// NoteFromZBits converts fractional Z-bit difficulty to a Sharenote while preserving precision.
-func NoteFromZBits(zbits float64) (Sharenote, error) {
-	if !isFinite(zbits) {
+// ParseSolutionRate accepts solution-rate strings (e.g. "5 kSol/s", "2 MSol/s") and returns solutions per second.
+// "Sol" and "solutions" scale exactly like "H", so the SI prefix rules match ParseHashrate.
+func ParseSolutionRate(input string) (float64, error) {
+	value, unitRaw, err := splitHashrateString(input)
+	if err != nil {
+		return 0, err
+	}
+	unit := solutionUnitReplacer.Replace(strings.ToUpper(unitRaw))
+	exponent, _, err := resolveHashrateUnit(unit)
+	if err != nil {
+		return 0, fmt.Errorf("unrecognised solution rate unit: %q", unitRaw)
+	}
+	return value * math.Pow(10, float64(exponent*3)), nil
+}
+
+// splitHashrateString validates the magnitude of a rate string and returns it with the raw unit text.
+func splitHashrateString(input string) (float64, string, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return 0, "", errors.New("hashrate string must not be empty")
+	}
+	match := hashrateStringPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return 0, "", fmt.Errorf("unrecognised hashrate format: %q", input)
+	}
+	magnitudeStr := strings.NewReplacer("_", "", ",", "").Replace(match[1])
+	value, err := strconv.ParseFloat(magnitudeStr, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("parse hashrate magnitude: %w", err)
+	}
+	if !isFinite(value) {
+		return 0, "", errors.New("hashrate magnitude must be finite")
+	}
+	if value < 0 {
+		return 0, "", errors.New("hashrate must be >= 0")
+	}
+	unitRaw := ""
+	if len(match) > 2 {
+		unitRaw = strings.TrimSpace(match[2])
+	}
+	return value, unitRaw, nil
+}
+
+// ParseDifficulty accepts SI-suffixed difficulty strings (e.g. "26.64 T", "500K") and returns the raw difficulty.
+func ParseDifficulty(input string) (float64, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return 0, errors.New("difficulty string must not be empty")
+	}
+	match := difficultyStringPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return 0, fmt.Errorf("unrecognised difficulty format: %q", input)
+	}
+	value, err := strconv.ParseFloat(strings.NewReplacer("_", "", ",", "").Replace(match[1]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse difficulty magnitude: %w", err)
+	}
+	if !isFinite(value) {
+		return 0, errors.New("difficulty magnitude must be finite")
+	}
+	exponent := hashratePrefixExponent[strings.ToUpper(match[2])]
+	return value * math.Pow(1000, float64(exponent)), nil
+}
+
+// FormatDifficulty renders a difficulty with two decimals and an SI suffix up to P (e.g. "26.64 T").
+func FormatDifficulty(difficulty float64) string {
+	if !isFinite(difficulty) || difficulty <= 0 {
+		return "0"
+	}
+	index := int(math.Max(0, math.Floor(math.Log10(difficulty)/3)))
+	if index >= len(difficultyPrefixes) {
+		index = len(difficultyPrefixes) - 1
+	}
+	scaled := difficulty / math.Pow(1000, float64(index))
+	if index == 0 {
+		return fmt.Sprintf("%.2f", scaled)
+	}
+	return fmt.Sprintf("%.2f %s", scaled, difficultyPrefixes[index])
+}
+
+// ParseDurationHuman accepts human-readable windows (e.g. "500 ms", "3.2 days") and returns seconds.
+// Recognised units are ms, s, min, h, day(s), and year(s); a year is 365.25 days.
+func ParseDurationHuman(input string) (float64, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return 0, errors.New("duration string must not be empty")
+	}
+	match := durationStringPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return 0, fmt.Errorf("unrecognised duration format: %q", input)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse duration magnitude: %w", err)
+	}
+	if !isFinite(value) {
+		return 0, errors.New("duration magnitude must be finite")
+	}
+	if value < 0 {
+		return 0, errors.New("duration must be >= 0")
+	}
+	scale, ok := durationUnitSeconds[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("unrecognised duration unit: %q", match[2])
+	}
+	return value * scale, nil
+}
+
+// NormalizeLabel canonicalises any accepted label spelling (e.g. "33.53Z", "33Z 53CZ") to "33Z53".
+func NormalizeLabel(input string) (string, error) {
+	note, err := parseLabel(input)
+	if err != nil {
+		return "", err
+	}
+	return note.Label(), nil
+}
+
+// parseLabel converts textual labels (33Z53, 33.53Z, 33Z 53CZ) into a Sharenote.
+func parseLabel(label string) (Sharenote, error) {
+	cleaned := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(strings.Map(foldLabelRune, label)), " ", ""))
+
+	// A bare "Z" is shorthand for 0Z00.
+	if cleaned == "Z" {
+		return noteFromComponents(0, 0)
+	}
+
+	// Spaces are already stripped, so "33Z53", "33Z53CZ", "33Z5CZ" and "33Z 5 CZ" all reach reStandard;
+	// the cent group is an integer count, so a single digit means 0N cents ("33Z5CZ" is 33Z05).
+	if match := reStandard.FindStringSubmatch(cleaned); match != nil {
+		z, err := strconv.Atoi(match[1])
+		if err != nil {
+			return Sharenote{}, fmt.Errorf("parse z: %w", err)
+		}
+		cents := 0
+		if match[2] != "" {
+			cents, _ = strconv.Atoi(match[2])
+		}
+		return noteFromComponents(z, cents)
+	}
+
+	if match := reDotted.FindStringSubmatch(cleaned); match != nil {
+		z, err := strconv.Atoi(match[1])
+		if err != nil {
+			return Sharenote{}, fmt.Errorf("parse z: %w", err)
+		}
+		decimals := match[2]
+		if len(decimals) < 2 {
+			decimals = decimals + strings.Repeat("0", 2-len(decimals))
+		} else if len(decimals) > 2 {
+			decimals = decimals[:2]
+		}
+		cents, _ := strconv.Atoi(decimals)
+		return noteFromComponents(z, cents)
+	}
+
+	if match := reDecimal.FindStringSubmatch(cleaned); match != nil {
+		zbits, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return Sharenote{}, fmt.Errorf("parse zbits: %w", err)
+		}
+		return NoteFromZBits(zbits)
+	}
+
+	return Sharenote{}, fmt.Errorf("unrecognised Sharenote label %q", label)
+}
+
+// foldLabelRune maps copy-paste confusables onto ASCII: Unicode spaces become ' ' and
+// fullwidth forms (e.g. 'Ｚ', '３') their ASCII counterparts. Dashes are left alone and rejected.
+func foldLabelRune(r rune) rune {
+	switch {
+	case r == '\u00A0', r == '\u2007', r == '\u202F', r == '\u3000':
+		return ' '
+	case r >= '\uFF01' && r <= '\uFF5E':
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// noteFromComponents normalises (Z, cents) into a Sharenote struct using cent-Z precision.
+func noteFromComponents(z, cents int) (Sharenote, error) {
+	if z < 0 {
+		return Sharenote{}, errors.New("z must be non-negative")
+	}
+	if z > maxLabelZ {
+		return Sharenote{}, fmt.Errorf("z must be <= %d", maxLabelZ)
+	}
+	c := clampCents(cents)
+	zbits := float64(z) + float64(c)*CentZBitStep
+	return Sharenote{Z: z, Cents: c, ZBits: zbits}, nil
+}
+
+// NoteFromComponents constructs a canonical Sharenote from integer Z and cent-Z precision.
+// It preserves the legacy cent-Z precision behaviour for callers that rely on (Z, CZ) inputs.
+func NoteFromComponents(z, cents int) (Sharenote, error) {
+	return noteFromComponents(z, cents)
+}
+
+// NoteFromZInteger interprets z as a whole-Z note with zero cents (e.g. 33 -> 33Z00).
+func NoteFromZInteger(z int) (Sharenote, error) {
+	return noteFromComponents(z, 0)
+}
+
+// NoteForLeadingZeroBits returns the note nZ00 whose target 2^(256-n) demands n leading zero bits.
+func NoteForLeadingZeroBits(n int) (Sharenote, error) {
+	if n < 0 || n > 256 {
+		return Sharenote{}, errors.New("leading zero bits must be in [0,256]")
+	}
+	return noteFromComponents(n, 0)
+}
+
+// NoteFromLeadingZeros returns the note achieved by a hash observed with the given count of leading
+// zero bits. Such a hash lies below 2^(256-bits), so this is NoteForLeadingZeroBits viewed from the
+// result side: requirement and achievement share the same nZ00 note.
+func NoteFromLeadingZeros(bits int) (Sharenote, error) {
+	return NoteForLeadingZeroBits(bits)
+}
+
+// LeadingZeroBits returns the whole leading zero bits a note demands, floor(zbits).
+func LeadingZeroBits(note any) (int, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Floor(resolved.ZBits)), nil
+}
+
+// NoteFromComponentsStrict is NoteFromComponents without clamping: cents outside [0,99] is an error.
+func NoteFromComponentsStrict(z, cents int) (Sharenote, error) {
+	if z < 0 {
+		return Sharenote{}, errors.New("z must be non-negative")
+	}
+	if cents < MinCentZ || cents > MaxCentZ {
+		return Sharenote{}, fmt.Errorf("cents must be in [%d,%d], got %d", MinCentZ, MaxCentZ, cents)
+	}
+	return noteFromComponents(z, cents)
+}
+
+// CentZFloorEpsilon is added, in cent-Z units, before flooring zbits onto the cent-Z grid. It absorbs
+// float64 error in values such as 33 + 0.53 that land a hair below their intended cent; values more than
+// this far below a boundary floor to the previous cent. Raise it only if inputs carry more accumulated
+// error; set it before concurrent use, as it is read without synchronisation.
+var CentZFloorEpsilon = 1e-9
+
+func labelComponentsFromZBits(zbits float64) (int, int) {
+	z := int(math.Floor(zbits))
+	if z < 0 {
+		z = 0
+	}
+	fractional := zbits - float64(z)
+	rawCents := int(math.Floor((fractional / CentZBitStep) + CentZFloorEpsilon))
+	return z, clampCents(rawCents)
+}
+
+// NoteFromZBits converts fractional Z-bit difficulty to a Sharenote while preserving precision.
+func NoteFromZBits(zbits float64) (Sharenote, error) {
+	if !isFinite(zbits) {
 		return Sharenote{}, errors.New("zbits must be finite")
 	}
 	if zbits < 0 {
 		return Sharenote{}, errors.New("zbits must be non-negative")
 	}
+	if zbits >= float64(maxLabelZ+1) {
+		return Sharenote{}, fmt.Errorf("zbits must be < %d", maxLabelZ+1)
+	}
 	z, cents := labelComponentsFromZBits(zbits)
 	return Sharenote{Z: z, Cents: cents, ZBits: zbits}, nil
 }
 
+// NoteFromDifficulty converts a raw difficulty (e.g. from ParseDifficulty) into a Sharenote.
+func NoteFromDifficulty(difficulty float64) (Sharenote, error) {
+	zbits, err := zBitsFromDifficulty(difficulty)
+	if err != nil {
+		return Sharenote{}, err
+	}
+	return NoteFromZBits(zbits)
+}
+
+// NoteFromBitsString parses zbits written as "33.53 bits" or "57b" and converts them to a Sharenote.
+func NoteFromBitsString(input string) (Sharenote, error) {
+	match := bitsStringPattern.FindStringSubmatch(strings.TrimSpace(input))
+	if match == nil {
+		return Sharenote{}, fmt.Errorf("unrecognised bits format: %q", input)
+	}
+	zbits, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return Sharenote{}, fmt.Errorf("parse bits magnitude: %w", err)
+	}
+	return NoteFromZBits(zbits)
+}
+
 // MustNoteFromZBits wraps NoteFromZBits and panics on failure. Intended for tests and fixtures.
 func MustNoteFromZBits(zbits float64) Sharenote {
 	note, err := NoteFromZBits(zbits)
@@ -584,6 +1364,24 @@ func MustNoteFromZBits(zbits float64) Sharenote {
 	return note
 }
 
+// BoundingNotes returns the cent-Z grid notes immediately below and above zbits.
+// When zbits already sits on the grid, lower and upper are the same note.
+func BoundingNotes(zbits float64) (lower, upper Sharenote, err error) {
+	note, err := NoteFromZBits(zbits)
+	if err != nil {
+		return Sharenote{}, Sharenote{}, err
+	}
+	lower = note.Quantize()
+	if math.Abs(zbits-lower.ZBits) < 1e-9 {
+		return lower, lower, nil
+	}
+	upper, err = lower.Next()
+	if err != nil {
+		return Sharenote{}, Sharenote{}, err
+	}
+	return lower, upper, nil
+}
+
 // NoteFromCentZBits converts cent-Z units (e.g. 3353 => 33.53Z) into a Sharenote.
 func NoteFromCentZBits(centZ int) (Sharenote, error) {
 	if centZ < 0 {
@@ -594,6 +1392,22 @@ func NoteFromCentZBits(centZ int) (Sharenote, error) {
 	return noteFromComponents(z, cents)
 }
 
+// NoteFromCentZDecimal reads the spreadsheet shorthand where 33.53 means the label 33Z53.
+//
+// WARNING: this is NOT the zbits interpretation used by EnsureNote(float64) and NoteFromZBits.
+// The fractional part is rounded to the nearest cent (33.529 -> 33Z53, where zbits would floor
+// to 33Z52), and the resulting note sits exactly on the cent-Z grid.
+func NoteFromCentZDecimal(v float64) (Sharenote, error) {
+	if !isFinite(v) || v < 0 {
+		return Sharenote{}, errors.New("cent-z decimal must be a finite, non-negative number")
+	}
+	centZ := math.Round(v * float64(centZUnitsPerZ))
+	if centZ >= math.MaxInt {
+		return Sharenote{}, errors.New("cent-z decimal out of range")
+	}
+	return NoteFromCentZBits(int(centZ))
+}
+
 // MustNoteFromCentZBits wraps NoteFromCentZBits and panics on failure. Intended for tests and fixtures.
 func MustNoteFromCentZBits(centZ int) Sharenote {
 	note, err := NoteFromCentZBits(centZ)
@@ -615,6 +1429,8 @@ func EnsureNote(input any) (Sharenote, error) {
 		return *v, nil
 	case string:
 		return parseLabel(v)
+	case json.RawMessage:
+		return noteFromRawJSON(v)
 	case float64:
 		return NoteFromZBits(v)
 	case float32:
@@ -644,6 +1460,23 @@ func EnsureNote(input any) (Sharenote, error) {
 	}
 }
 
+// noteFromRawJSON interprets a JSON string as a label and a JSON number as zbits.
+func noteFromRawJSON(raw json.RawMessage) (Sharenote, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, `"`) {
+		var label string
+		if err := json.Unmarshal([]byte(trimmed), &label); err != nil {
+			return Sharenote{}, fmt.Errorf("decode note label: %w", err)
+		}
+		return parseLabel(label)
+	}
+	var zbits float64
+	if err := json.Unmarshal([]byte(trimmed), &zbits); err != nil {
+		return Sharenote{}, fmt.Errorf("decode note zbits: %w", err)
+	}
+	return NoteFromZBits(zbits)
+}
+
 // ProbabilityFromZBits returns 2^(-zbits).
 func ProbabilityFromZBits(zbits float64) (float64, error) {
 	if !isFinite(zbits) {
@@ -707,13 +1540,126 @@ func ExpectedHashesMeasurement(note any) (HashesMeasurement, error) {
 	return ExpectedHashesForNote(note)
 }
 
+// TotalExpectedHashes sums the expected hashes of each note, e.g. for a session minting them in order.
+func TotalExpectedHashes(notes []any) (HashesMeasurement, error) {
+	if len(notes) == 0 {
+		return HashesMeasurement{}, errors.New("notes slice must not be empty")
+	}
+	total := 0.0
+	for i, note := range notes {
+		expected, err := ExpectedHashesForNote(note)
+		if err != nil {
+			return HashesMeasurement{}, fmt.Errorf("note %d: %w", i, err)
+		}
+		total += expected.Value
+		if !isFinite(total) {
+			return HashesMeasurement{}, errors.New("total expected hashes overflow")
+		}
+	}
+	return HashesMeasurement{Value: total}, nil
+}
+
+// RewardEntry pairs a note with the payout earned when it is minted.
+type RewardEntry struct {
+	Note   any
+	Reward float64
+}
+
+// ExpectedRewardPerHash returns sum(reward_i * 2^(-zbits_i)) across the provided entries.
+func ExpectedRewardPerHash(entries []RewardEntry) (float64, error) {
+	total := 0.0
+	for _, entry := range entries {
+		if !isFinite(entry.Reward) {
+			return 0, errors.New("reward must be finite")
+		}
+		p, err := ProbabilityPerHash(entry.Note)
+		if err != nil {
+			return 0, err
+		}
+		total += entry.Reward * p
+	}
+	return total, nil
+}
+
+// RewardCurve maps note rarity to a payout of base * growth^zbits.
+type RewardCurve struct {
+	base   float64
+	growth float64
+}
+
+// NewRewardCurve constructs a RewardCurve; base and growth must be finite and > 0.
+func NewRewardCurve(base, growth float64) (RewardCurve, error) {
+	if !isFinite(base) || base <= 0 {
+		return RewardCurve{}, errors.New("base must be > 0")
+	}
+	if !isFinite(growth) || growth <= 0 {
+		return RewardCurve{}, errors.New("growth must be > 0")
+	}
+	return RewardCurve{base: base, growth: growth}, nil
+}
+
+// Reward returns the payout for the provided note.
+func (c RewardCurve) Reward(note any) (float64, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return 0, err
+	}
+	reward := c.base * math.Pow(c.growth, resolved.ZBits)
+	if !isFinite(reward) {
+		return 0, errors.New("reward overflow")
+	}
+	return reward, nil
+}
+
+// TierBreakpoint names the tier covering notes up to and including MaxZBits.
+type TierBreakpoint struct {
+	MaxZBits float64
+	Name     string
+}
+
+// TierTable buckets notes into named tiers by Z-bit difficulty.
+type TierTable struct {
+	breakpoints []TierBreakpoint
+}
+
+// NewTierTable constructs a TierTable from strictly ascending breakpoints. Each tier's upper bound is
+// inclusive, so a note exactly at a breakpoint belongs to the lower tier; use +Inf for an open-ended top tier.
+func NewTierTable(breakpoints ...TierBreakpoint) (TierTable, error) {
+	if len(breakpoints) == 0 {
+		return TierTable{}, errors.New("tier table needs at least one breakpoint")
+	}
+	for i, bp := range breakpoints {
+		if math.IsNaN(bp.MaxZBits) || bp.MaxZBits < 0 {
+			return TierTable{}, fmt.Errorf("tier %q: max zbits must be >= 0", bp.Name)
+		}
+		if i > 0 && bp.MaxZBits <= breakpoints[i-1].MaxZBits {
+			return TierTable{}, fmt.Errorf("tier %q: breakpoints must be strictly ascending", bp.Name)
+		}
+	}
+	return TierTable{breakpoints: slices.Clone(breakpoints)}, nil
+}
+
+// Tier returns the name of the tier containing the note.
+func (t TierTable) Tier(note any) (string, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return "", err
+	}
+	for _, bp := range t.breakpoints {
+		if resolved.ZBits <= bp.MaxZBits {
+			return bp.Name, nil
+		}
+	}
+	return "", fmt.Errorf("note %s is above the highest tier", resolved.Label())
+}
+
 func requiredHashrateValue(note any, seconds float64, opts ...HashrateOption) (float64, error) {
 	if !isFinite(seconds) || seconds <= 0 {
 		return 0, errors.New("seconds must be > 0")
 	}
-	cfg := hashrateOptions{multiplier: 1}
-	for _, opt := range opts {
-		opt(&cfg)
+	cfg := resolveHashrateOptions(opts)
+	if cfg.err != nil {
+		return 0, cfg.err
 	}
 	if cfg.multiplier <= 0 {
 		return 0, errors.New("multiplier must be > 0")
@@ -730,7 +1676,11 @@ func requiredHashrateValue(note any, seconds float64, opts ...HashrateOption) (f
 }
 
 // RequiredHashrate computes multiplier * expected_hashes / seconds and returns a measurement.
+// Without a multiplier, confidence, or reliability option it uses the SetDefaultReliability preset.
 func RequiredHashrate(note any, seconds float64, opts ...HashrateOption) (HashrateMeasurement, error) {
+	if !resolveHashrateOptions(opts).multiplierSet {
+		opts = append(slices.Clip(opts), WithMultiplier(defaultReliabilityLevel().Multiplier))
+	}
 	value, err := requiredHashrateValue(note, seconds, opts...)
 	if err != nil {
 		return HashrateMeasurement{}, err
@@ -738,9 +1688,18 @@ func RequiredHashrate(note any, seconds float64, opts ...HashrateOption) (Hashra
 	return HashrateMeasurement{Value: value}, nil
 }
 
+// RequiredHashrateString returns the required hashrate already humanised, e.g. "7.43 GH/s".
+func RequiredHashrateString(note any, seconds float64, opts ...HashrateOption) (string, error) {
+	measurement, err := RequiredHashrate(note, seconds, opts...)
+	if err != nil {
+		return "", err
+	}
+	return HumaniseHashrate(measurement.Value).String(), nil
+}
+
 // RequiredHashrateMean returns the mean hashrate.
 func RequiredHashrateMean(note any, seconds float64) (HashrateMeasurement, error) {
-	return RequiredHashrate(note, seconds)
+	return RequiredHashrate(note, seconds, WithMultiplier(1))
 }
 
 // RequiredHashrateQuantile returns the quantile hashrate for the provided confidence.
@@ -767,16 +1726,24 @@ func RequiredHashrateQuantileMeasurement(note any, seconds, confidence float64)
 	return RequiredHashrateQuantile(note, seconds, confidence)
 }
 
+// InterpolatedHashrate returns the hashrate needed to mint the note within seconds at an arbitrary confidence.
+// The result is computed exactly from the Poisson multiplier -ln(1-confidence), so values between the
+// reliability presets carry no interpolation error.
+func InterpolatedHashrate(note any, seconds, confidence float64) (HashrateMeasurement, error) {
+	if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+		return HashrateMeasurement{}, errors.New("confidence must be in (0,1)")
+	}
+	return RequiredHashrateQuantile(note, seconds, confidence)
+}
+
 // HashrateRangeForNote returns the [min,max) hashrate interval corresponding to the provided note label.
 func HashrateRangeForNote(note any, seconds float64, opts ...HashrateOption) (HashrateRange, error) {
 	if !isFinite(seconds) || seconds <= 0 {
 		return HashrateRange{}, errors.New("seconds must be > 0")
 	}
-	cfg := hashrateOptions{multiplier: 1}
-	for _, opt := range opts {
-		if opt != nil {
-			opt(&cfg)
-		}
+	cfg := resolveHashrateOptions(opts)
+	if cfg.err != nil {
+		return HashrateRange{}, cfg.err
 	}
 	if cfg.multiplier <= 0 {
 		return HashrateRange{}, errors.New("multiplier must be > 0")
@@ -795,12 +1762,74 @@ func HashrateRangeForNote(note any, seconds float64, opts ...HashrateOption) (Ha
 	}
 	lower := lowerExpected * cfg.multiplier / seconds
 	upper := upperExpected * cfg.multiplier / seconds
+	if cfg.closedUpper {
+		upper *= 1 - closedUpperEpsilon
+	}
 	if upper < lower {
 		upper = lower
 	}
 	return HashrateRange{Min: lower, Max: upper}, nil
 }
 
+// HashrateRangeForZBand spans the whole Z band, from zZ00's lower bound to zZ99's upper bound.
+func HashrateRangeForZBand(z int, seconds float64, opts ...HashrateOption) (HashrateRange, error) {
+	if z < 0 {
+		return HashrateRange{}, errors.New("z must be >= 0")
+	}
+	first, err := NoteFromComponents(z, 0)
+	if err != nil {
+		return HashrateRange{}, err
+	}
+	last, err := NoteFromComponents(z, 99)
+	if err != nil {
+		return HashrateRange{}, err
+	}
+	lower, err := HashrateRangeForNote(first, seconds, opts...)
+	if err != nil {
+		return HashrateRange{}, err
+	}
+	upper, err := HashrateRangeForNote(last, seconds, opts...)
+	if err != nil {
+		return HashrateRange{}, err
+	}
+	return HashrateRange{Min: lower.Min, Max: upper.Max}, nil
+}
+
+// MarginalDifficultyRatio returns how much harder the next cent-Z note is than the note itself,
+// 2^(next.ZBits - zbits). Grid-aligned notes give 2^0.01; sub-cent zbits give proportionally less.
+func MarginalDifficultyRatio(note any) (float64, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return 0, err
+	}
+	next, err := resolved.Next()
+	if err != nil {
+		return 0, err
+	}
+	return math.Exp2(next.ZBits - resolved.ZBits), nil
+}
+
+// HashrateStepToNextNote returns the extra H/s needed to move from the note to the next cent-Z note in the same window.
+func HashrateStepToNextNote(note any, seconds float64, opts ...HashrateOption) (float64, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return 0, err
+	}
+	current, err := requiredHashrateValue(resolved, seconds, opts...)
+	if err != nil {
+		return 0, err
+	}
+	nextNote, err := resolved.Next()
+	if err != nil {
+		return 0, err
+	}
+	next, err := requiredHashrateValue(nextNote, seconds, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return next - current, nil
+}
+
 // MaxZBitsForHashrate computes the maximum bit difficulty achievable with the provided parameters.
 func MaxZBitsForHashrate(hashrate, seconds, multiplier float64) (float64, error) {
 	if !isFinite(hashrate) || hashrate <= 0 {
@@ -815,42 +1844,317 @@ func MaxZBitsForHashrate(hashrate, seconds, multiplier float64) (float64, error)
 	return math.Log2(hashrate * seconds / multiplier), nil
 }
 
+// WindowForNote returns the seconds a rig at hashrate needs to mint the note with the given confidence,
+// expected_hashes * -ln(1-confidence) / hashrate.
+func WindowForNote(note any, hashrate, confidence float64) (float64, error) {
+	if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+		return 0, errors.New("confidence must be in (0,1)")
+	}
+	if !isFinite(hashrate) || hashrate <= 0 {
+		return 0, errors.New("hashrate must be > 0")
+	}
+	hashes, err := requiredHashrateValue(note, 1, WithConfidence(confidence))
+	if err != nil {
+		return 0, err
+	}
+	return hashes / hashrate, nil
+}
+
+// SuccessProbability returns the chance of minting the note within seconds at hashrate,
+// 1 - exp(-hashrate*seconds/expected_hashes).
+func SuccessProbability(note any, hashrate, seconds float64) (float64, error) {
+	if !isFinite(hashrate) || hashrate < 0 {
+		return 0, errors.New("hashrate must be >= 0")
+	}
+	if !isFinite(seconds) || seconds <= 0 {
+		return 0, errors.New("seconds must be > 0")
+	}
+	lambda, err := poissonLambda(note, hashrate, seconds)
+	if err != nil {
+		return 0, err
+	}
+	return -math.Expm1(-lambda), nil
+}
+
+// ExpectedNotesInWindow returns the Poisson mean number of notes minted within seconds at hashrate,
+// hashrate*seconds/expected_hashes; it is the lambda behind SuccessProbability.
+func ExpectedNotesInWindow(note any, hashrate, seconds float64) (float64, error) {
+	if !isFinite(hashrate) || hashrate <= 0 {
+		return 0, errors.New("hashrate must be > 0")
+	}
+	if !isFinite(seconds) || seconds <= 0 {
+		return 0, errors.New("seconds must be > 0")
+	}
+	return poissonLambda(note, hashrate, seconds)
+}
+
+func poissonLambda(note any, hashrate, seconds float64) (float64, error) {
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return 0, err
+	}
+	expected, err := expectedHashesValueFromZBits(resolved.ZBits)
+	if err != nil {
+		return 0, err
+	}
+	return hashrate * seconds / expected, nil
+}
+
+// FleetCoverage returns the fraction of rigs whose hashrate meets the requirement to mint the note
+// within seconds at the given confidence.
+func FleetCoverage(note any, hashrates []float64, seconds, confidence float64) (fraction float64, err error) {
+	if len(hashrates) == 0 {
+		return 0, errors.New("hashrates slice must not be empty")
+	}
+	if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+		return 0, errors.New("confidence must be in (0,1)")
+	}
+	required, err := requiredHashrateValue(note, seconds, WithConfidence(confidence))
+	if err != nil {
+		return 0, err
+	}
+	covered := 0
+	for i, hashrate := range hashrates {
+		if !isFinite(hashrate) || hashrate < 0 {
+			return 0, fmt.Errorf("hashrate %d must be finite and >= 0", i)
+		}
+		if hashrate >= required {
+			covered++
+		}
+	}
+	return float64(covered) / float64(len(hashrates)), nil
+}
+
+// ProbabilityForAttempts returns the chance of at least one success in attempts hashes,
+// 1-(1-p)^attempts, evaluated as -expm1(attempts*log1p(-p)) for stability.
+func ProbabilityForAttempts(note any, attempts float64) (float64, error) {
+	if !isFinite(attempts) || attempts < 0 {
+		return 0, errors.New("attempts must be >= 0")
+	}
+	p, err := ProbabilityPerHash(note)
+	if err != nil {
+		return 0, err
+	}
+	return -math.Expm1(attempts * math.Log1p(-p)), nil
+}
+
+// CompareRigs returns each rig's window to mint the note at the configured reliability.
+func CompareRigs(note any, hashrateA, hashrateB float64, opts ...HashrateOption) (secondsA, secondsB float64, err error) {
+	if !isFinite(hashrateA) || hashrateA <= 0 || !isFinite(hashrateB) || hashrateB <= 0 {
+		return 0, 0, errors.New("hashrates must be > 0")
+	}
+	// The hashrate needed over one second equals the hashes required, so dividing by each rig gives its window.
+	hashes, err := requiredHashrateValue(note, 1, opts...)
+	if err != nil {
+		return 0, 0, err
+	}
+	return hashes / hashrateA, hashes / hashrateB, nil
+}
+
+// defaultQuantileReservoir is the sample capacity of a zero-value HashrateQuantiles.
+const defaultQuantileReservoir = 1024
+
+// HashrateQuantiles tracks running percentiles of observed hashrates using reservoir sampling:
+// the first Capacity samples are kept exactly, after which each new sample replaces a random one.
+// The zero value is ready to use with a capacity of 1024. It is not safe for concurrent use.
+type HashrateQuantiles struct {
+	// Capacity bounds the reservoir; zero selects the default.
+	Capacity int
+	samples  []float64
+	seen     uint64
+	rng      *rand.Rand
+}
+
+// Observe records a hashrate sample in H/s; non-finite and negative values are ignored.
+func (q *HashrateQuantiles) Observe(hashrate float64) {
+	if !isFinite(hashrate) || hashrate < 0 {
+		return
+	}
+	capacity := q.Capacity
+	if capacity <= 0 {
+		capacity = defaultQuantileReservoir
+	}
+	q.seen++
+	if len(q.samples) < capacity {
+		q.samples = append(q.samples, hashrate)
+		return
+	}
+	if q.rng == nil {
+		// A fixed seed keeps percentiles reproducible for the same input stream.
+		q.rng = rand.New(rand.NewPCG(1, 2))
+	}
+	if j := q.rng.Uint64N(q.seen); j < uint64(len(q.samples)) {
+		q.samples[j] = hashrate
+	}
+}
+
+// Count returns the number of samples observed, including any no longer held in the reservoir.
+func (q *HashrateQuantiles) Count() uint64 {
+	return q.seen
+}
+
+// Percentile returns the p-quantile (p in [0,1]) of the retained samples with linear interpolation,
+// or NaN when nothing has been observed or p is out of range. Pass the result to NoteFromHashrate.
+func (q *HashrateQuantiles) Percentile(p float64) float64 {
+	if len(q.samples) == 0 || !(p >= 0 && p <= 1) {
+		return math.NaN()
+	}
+	sorted := slices.Clone(q.samples)
+	slices.Sort(sorted)
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
+
 // NoteFromHashrate inverts RequiredHashrate using a structured hashrate input.
+// The label is floored to cent-Z by default; WithNoteRounding(RoundingNearest) snaps to the closest grid note instead.
 func NoteFromHashrate(hashrate HashrateValue, seconds float64, opts ...HashrateOption) (Sharenote, error) {
 	numeric, err := NormalizeHashrateValue(hashrate)
 	if err != nil {
 		return Sharenote{}, err
 	}
-	cfg := hashrateOptions{multiplier: 1}
-	for _, opt := range opts {
-		opt(&cfg)
+	cfg := resolveHashrateOptions(opts)
+	if cfg.err != nil {
+		return Sharenote{}, cfg.err
 	}
 	zbits, err := MaxZBitsForHashrate(numeric, seconds, cfg.multiplier)
 	if err != nil {
 		return Sharenote{}, err
 	}
+	if cfg.rounding == RoundingNearest {
+		return NoteFromCentZBits(int(math.Round(zbits * float64(centZUnitsPerZ))))
+	}
 	return NoteFromZBits(zbits)
 }
 
+// NoteFromHashBudget returns the note a budget of hashes spread evenly over the window can mint.
+func NoteFromHashBudget(hashes, seconds float64, opts ...HashrateOption) (Sharenote, error) {
+	if !isFinite(hashes) || hashes <= 0 {
+		return Sharenote{}, errors.New("hashes must be > 0")
+	}
+	if !isFinite(seconds) || seconds <= 0 {
+		return Sharenote{}, errors.New("seconds must be > 0")
+	}
+	return NoteFromHashrate(HashrateValue{Value: hashes / seconds}, seconds, opts...)
+}
+
+// EmpiricalNote backs out the note implied by observed mining data: hashes/successes expected hashes,
+// i.e. log2(hashes/successes) zbits.
+func EmpiricalNote(hashes float64, successes int) (Sharenote, error) {
+	if !isFinite(hashes) || hashes <= 0 {
+		return Sharenote{}, errors.New("hashes must be > 0")
+	}
+	if successes < 1 {
+		return Sharenote{}, errors.New("successes must be >= 1")
+	}
+	return NoteFromDifficulty(hashes / float64(successes))
+}
+
+// MaxNoteForHashrate returns the hardest note the rig can mint within the window at the configured reliability.
+// It always floors to cent-Z, ignoring WithNoteRounding, so the result is guaranteed achievable.
+func MaxNoteForHashrate(hashrate HashrateValue, seconds float64, opts ...HashrateOption) (Sharenote, error) {
+	floored := make([]HashrateOption, 0, len(opts)+1)
+	floored = append(floored, opts...)
+	floored = append(floored, WithNoteRounding(RoundingFloor))
+	return NoteFromHashrate(hashrate, seconds, floored...)
+}
+
+// AchievableNotes lists every cent-Z note from 0Z00 up to MaxNoteForHashrate, easiest first.
+// Ranges longer than maxAchievableNotes (256 whole Z) are rejected.
+func AchievableNotes(hashrate HashrateValue, seconds float64, opts ...HashrateOption) ([]Sharenote, error) {
+	maxNote, err := MaxNoteForHashrate(hashrate, seconds, opts...)
+	if err != nil {
+		return nil, err
+	}
+	count := maxNote.centZ() + 1
+	if count > maxAchievableNotes {
+		return nil, fmt.Errorf("achievable range of %d notes exceeds limit of %d", count, maxAchievableNotes)
+	}
+	notes := make([]Sharenote, 0, count)
+	for centZ := 0; centZ < count; centZ++ {
+		note, err := NoteFromCentZBits(centZ)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+// FilterNotes returns the notes for which pred reports true, preserving order.
+func FilterNotes(notes []Sharenote, pred func(Sharenote) bool) []Sharenote {
+	filtered := make([]Sharenote, 0, len(notes))
+	for _, note := range notes {
+		if pred(note) {
+			filtered = append(filtered, note)
+		}
+	}
+	return filtered
+}
+
+// MapNotes applies fn to each note in order, stopping at the first error.
+func MapNotes[T any](notes []Sharenote, fn func(Sharenote) (T, error)) ([]T, error) {
+	results := make([]T, len(notes))
+	for i, note := range notes {
+		value, err := fn(note)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = value
+	}
+	return results, nil
+}
+
 // TargetFor returns the integer hash target for the note.
 func TargetFor(note any) (*big.Int, error) {
+	target := new(big.Int)
+	if err := TargetForInto(note, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// TargetForInto writes the note's integer hash target into dst, reusing its storage.
+func TargetForInto(note any, dst *big.Int) error {
+	if dst == nil {
+		return errors.New("dst must not be nil")
+	}
 	resolved, err := EnsureNote(note)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	integerBits := int(math.Floor(resolved.ZBits))
 	baseExponent := 256 - integerBits
 	if baseExponent < 0 {
-		return nil, errors.New("z too large; target underflow")
+		return errors.New("z too large; target underflow")
 	}
 	fractional := resolved.ZBits - float64(integerBits)
 	scale := math.Exp2(-fractional)
 
 	const precisionBits = 48
 	scaleFactor := uint64(math.Round(scale * math.Exp2(precisionBits)))
-	base := new(big.Int).Lsh(big.NewInt(1), uint(baseExponent))
-	result := new(big.Int).Mul(base, new(big.Int).SetUint64(scaleFactor))
-	return result.Rsh(result, precisionBits), nil
+	dst.SetUint64(scaleFactor)
+	dst.Lsh(dst, uint(baseExponent))
+	dst.Rsh(dst, precisionBits)
+	return nil
+}
+
+// NotesShareTarget reports whether both notes resolve to the same integer hash target.
+// Sub-cent-Z differences that collapse under TargetFor's fixed-point rounding compare equal.
+func NotesShareTarget(a, b any) (bool, error) {
+	targetA, err := TargetFor(a)
+	if err != nil {
+		return false, err
+	}
+	targetB, err := TargetFor(b)
+	if err != nil {
+		return false, err
+	}
+	return targetA.Cmp(targetB) == 0, nil
 }
 
 // CompareNotes orders notes by rarity (higher Z first, then cents).
@@ -879,16 +2183,27 @@ func CompareNotes(a, b any) (int, error) {
 	}
 }
 
+// CompareNotesByTarget orders notes by their integer hash targets with CompareNotes' sign convention:
+// the smaller target is rarer and compares greater. Unlike CompareNotes it sees sub-cent-Z differences.
+func CompareNotesByTarget(a, b any) (int, error) {
+	targetA, err := TargetFor(a)
+	if err != nil {
+		return 0, err
+	}
+	targetB, err := TargetFor(b)
+	if err != nil {
+		return 0, err
+	}
+	return targetB.Cmp(targetA), nil
+}
+
 // NBitsToSharenote converts compact Bitcoin difficulty to a Sharenote.
 func NBitsToSharenote(hex string) (Sharenote, error) {
-	cleaned := strings.TrimSpace(strings.TrimPrefix(strings.ToLower(hex), "0x"))
-	if len(cleaned) != 8 {
-		return Sharenote{}, errors.New("nBits must be 8 hex characters")
-	}
-	value, err := strconv.ParseUint(cleaned, 16, 32)
+	compact, err := parseNBits(hex)
 	if err != nil {
-		return Sharenote{}, fmt.Errorf("parse nBits: %w", err)
+		return Sharenote{}, err
 	}
+	value := uint64(compact)
 	exponent := value >> 24
 	mantissa := value & 0xFFFFFF
 	if mantissa == 0 {
@@ -899,6 +2214,73 @@ func NBitsToSharenote(hex string) (Sharenote, error) {
 	return NoteFromZBits(zbits)
 }
 
+func parseNBits(hex string) (uint32, error) {
+	cleaned := strings.TrimSpace(strings.TrimPrefix(strings.ToLower(hex), "0x"))
+	if len(cleaned) != 8 {
+		return 0, errors.New("nBits must be 8 hex characters")
+	}
+	value, err := strconv.ParseUint(cleaned, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse nBits: %w", err)
+	}
+	return uint32(value), nil
+}
+
+func compactToTarget(compact uint32) (*big.Int, error) {
+	exponent := int(compact >> 24)
+	mantissa := compact & 0x007FFFFF
+	if compact&0x00800000 != 0 {
+		return nil, errors.New("nBits encodes a negative target")
+	}
+	if mantissa == 0 {
+		return nil, errors.New("mantissa must be non-zero")
+	}
+	target := new(big.Int).SetUint64(uint64(mantissa))
+	if exponent <= 3 {
+		return target.Rsh(target, uint(8*(3-exponent))), nil
+	}
+	return target.Lsh(target, uint(8*(exponent-3))), nil
+}
+
+// diff1Target is the pool difficulty-1 target, nBits 0x1d00ffff.
+var diff1Target = new(big.Int).Lsh(big.NewInt(0xFFFF), 208)
+
+// NBitsToPoolDifficulty converts compact nBits into pool difficulty relative to the difficulty-1 target.
+func NBitsToPoolDifficulty(hex string) (float64, error) {
+	compact, err := parseNBits(hex)
+	if err != nil {
+		return 0, err
+	}
+	target, err := compactToTarget(compact)
+	if err != nil {
+		return 0, err
+	}
+	if target.Sign() <= 0 {
+		return 0, errors.New("target must be positive")
+	}
+	ratio := new(big.Rat).SetFrac(diff1Target, target)
+	difficulty, _ := ratio.Float64()
+	return difficulty, nil
+}
+
+// PoolDifficultyToNBits encodes a pool difficulty as compact nBits hex, truncating to nBits precision.
+func PoolDifficultyToNBits(difficulty float64) (string, error) {
+	if !isFinite(difficulty) || difficulty <= 0 {
+		return "", errors.New("difficulty must be > 0")
+	}
+	quotient := new(big.Float).SetPrec(512).SetInt(diff1Target)
+	quotient.Quo(quotient, new(big.Float).SetPrec(512).SetFloat64(difficulty))
+	target, _ := quotient.Int(nil)
+	if target.Sign() <= 0 {
+		return "", errors.New("difficulty too large; target underflow")
+	}
+	compact, err := targetToCompact(target)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%08x", compact), nil
+}
+
 func targetToCompact(target *big.Int) (uint32, error) {
 	if target == nil || target.Sign() <= 0 {
 		return 0, errors.New("target must be positive")
@@ -938,15 +2320,149 @@ func SharenoteToNBits(note any) (string, error) {
 	return fmt.Sprintf("%08x", compact), nil
 }
 
-// ReliabilityLevels returns all predefined reliability presets.
-func ReliabilityLevels() []ReliabilityLevel {
-	return []ReliabilityLevel{
-		reliabilityLevels[ReliabilityMean],
-		reliabilityLevels[ReliabilityUsually90],
-		reliabilityLevels[ReliabilityOften95],
-		reliabilityLevels[ReliabilityVeryLikely99],
-		reliabilityLevels[ReliabilityAlmost999],
+// PoolDifficultyForNote returns the note's difficulty relative to the difficulty-1 target used by pools.
+func PoolDifficultyForNote(note any) (float64, error) {
+	target, err := TargetFor(note)
+	if err != nil {
+		return 0, err
+	}
+	if target.Sign() <= 0 {
+		return 0, errors.New("target must be positive")
+	}
+	ratio := new(big.Rat).SetFrac(diff1Target, target)
+	difficulty, _ := ratio.Float64()
+	return difficulty, nil
+}
+
+// PoolDifficulty returns the receiver's pool difficulty; see PoolDifficultyForNote.
+func (n Sharenote) PoolDifficulty() (float64, error) {
+	return PoolDifficultyForNote(n)
+}
+
+// SharenoteToTargetHex returns the note's hash target as a 64-character, zero-padded big-endian hex string.
+func SharenoteToTargetHex(note any) (string, error) {
+	target, err := TargetFor(note)
+	if err != nil {
+		return "", err
+	}
+	if target.BitLen() > 256 {
+		return "", errors.New("target does not fit in 256 bits")
+	}
+	return fmt.Sprintf("%064x", target), nil
+}
+
+// TargetHex returns the receiver's hash target as a 64-character hex string; see SharenoteToTargetHex.
+func (n Sharenote) TargetHex() (string, error) {
+	return SharenoteToTargetHex(n)
+}
+
+// ReliabilityLevels returns the built-in presets in canonical order, followed by any registered
+// custom levels sorted by ascending multiplier, then ID. The order is stable across calls.
+func ReliabilityLevels() []ReliabilityLevel {
+	levels := make([]ReliabilityLevel, 0, len(builtinReliabilityOrder))
+	for _, id := range builtinReliabilityOrder {
+		levels = append(levels, reliabilityLevels[id])
+	}
+	customReliabilityMu.RLock()
+	defer customReliabilityMu.RUnlock()
+	return append(levels, customReliabilityLevels...)
+}
+
+// ForEachReliabilityLevel calls fn for each level in ReliabilityLevels order without building a slice,
+// stopping early when fn returns false. Custom levels come from a snapshot taken before iterating,
+// so levels registered meanwhile (including by fn) are not visited. No lock is held while fn runs.
+func ForEachReliabilityLevel(fn func(ReliabilityLevel) bool) {
+	customReliabilityMu.RLock()
+	customs := customReliabilityLevels
+	customReliabilityMu.RUnlock()
+	for _, id := range builtinReliabilityOrder {
+		if !fn(reliabilityLevels[id]) {
+			return
+		}
+	}
+	for _, lvl := range customs {
+		if !fn(lvl) {
+			return
+		}
+	}
+}
+
+// NearestReliabilityLevel returns the level (built-in or registered) whose multiplier is closest to
+// the given one, comparing on a log scale so ratios weigh evenly; ties keep the earlier level.
+func NearestReliabilityLevel(multiplier float64) ReliabilityLevel {
+	levels := ReliabilityLevels()
+	best := levels[0]
+	if !isFinite(multiplier) || multiplier <= 0 {
+		return best
+	}
+	bestDistance := math.Inf(1)
+	for _, lvl := range levels {
+		if d := math.Abs(math.Log(multiplier / lvl.Multiplier)); d < bestDistance {
+			best, bestDistance = lvl, d
+		}
+	}
+	return best
+}
+
+// ReliabilityOption is a JSON-ready view of a reliability level for selection UIs.
+type ReliabilityOption struct {
+	ID         ReliabilityID `json:"id"`
+	Label      string        `json:"label"`
+	Multiplier float64       `json:"multiplier"`
+	Confidence *float64      `json:"confidence"`
+}
+
+// ReliabilityOptions returns the reliability levels as selectable options, in ReliabilityLevels order.
+func ReliabilityOptions() []ReliabilityOption {
+	levels := ReliabilityLevels()
+	options := make([]ReliabilityOption, 0, len(levels))
+	for _, lvl := range levels {
+		var confidence *float64
+		if lvl.Confidence != nil {
+			val := *lvl.Confidence
+			confidence = &val
+		}
+		options = append(options, ReliabilityOption{
+			ID:         lvl.ID,
+			Label:      lvl.Label,
+			Multiplier: lvl.Multiplier,
+			Confidence: confidence,
+		})
+	}
+	return options
+}
+
+// MultiplierEntry pairs a confidence level with its Poisson multiplier -ln(1-confidence).
+type MultiplierEntry struct {
+	Confidence float64
+	Multiplier float64
+}
+
+// MultiplierTable computes the Poisson multiplier for each confidence in (0,1), preserving input order.
+func MultiplierTable(confidences []float64) ([]MultiplierEntry, error) {
+	table := make([]MultiplierEntry, len(confidences))
+	for i, confidence := range confidences {
+		if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+			return nil, fmt.Errorf("confidence %d must be in (0,1), got %v", i, confidence)
+		}
+		table[i] = MultiplierEntry{Confidence: confidence, Multiplier: -math.Log(1 - confidence)}
+	}
+	return table, nil
+}
+
+// HashrateUnitInfo pairs a canonical hashrate unit with its power-of-1000 exponent.
+type HashrateUnitInfo struct {
+	Unit     HashrateUnit
+	Exponent int
+}
+
+// HashrateUnits returns the canonical hashrate units in ascending order.
+func HashrateUnits() []HashrateUnitInfo {
+	units := make([]HashrateUnitInfo, len(hashrateUnits))
+	for i, u := range hashrateUnits {
+		units[i] = HashrateUnitInfo{Unit: u.unit, Exponent: u.exponent}
 	}
+	return units
 }
 
 // FormatProbabilityDisplay returns strings like "1 / 2^33.00000000".
@@ -957,11 +2473,31 @@ func FormatProbabilityDisplay(zbits float64, precision int) string {
 	return fmt.Sprintf("1 / 2^%.*f", precision, zbits)
 }
 
+// FormatProbabilityDisplayTrim formats like FormatProbabilityDisplay but trims trailing zeros, e.g. "1 / 2^33.53".
+func FormatProbabilityDisplayTrim(zbits float64, maxPrecision int) string {
+	if maxPrecision < 0 {
+		maxPrecision = 0
+	}
+	digits := strconv.FormatFloat(zbits, 'f', maxPrecision, 64)
+	if strings.Contains(digits, ".") {
+		digits = strings.TrimRight(strings.TrimRight(digits, "0"), ".")
+	}
+	return "1 / 2^" + digits
+}
+
 // HumanHashrateOption customises display formatting when humanising H/s values.
 type HumanHashrateOption func(*humanHashrateOptions)
 
 type humanHashrateOptions struct {
 	precision *int
+	noSpace   bool
+}
+
+// WithHumanHashrateNoSpace drops the space between magnitude and unit (e.g. "3.20GH/s").
+func WithHumanHashrateNoSpace() HumanHashrateOption {
+	return func(cfg *humanHashrateOptions) {
+		cfg.noSpace = true
+	}
 }
 
 // WithHumanHashratePrecision forces a fixed number of decimal places in the display string.
@@ -974,6 +2510,26 @@ func WithHumanHashratePrecision(precision int) HumanHashrateOption {
 	}
 }
 
+// HashrateUnitFor returns the unit and exponent HumaniseHashrate would pick for hashrate.
+func HashrateUnitFor(hashrate float64) (HashrateUnit, int, error) {
+	if !isFinite(hashrate) || hashrate < 0 {
+		return "", 0, errors.New("hashrate must be a finite, non-negative number")
+	}
+	if hashrate == 0 {
+		return HashrateUnitHps, 0, nil
+	}
+	unit := hashrateUnits[hashrateUnitIndex(hashrate)]
+	return unit.unit, unit.exponent, nil
+}
+
+func hashrateUnitIndex(hashrate float64) int {
+	index := int(math.Max(0, math.Floor(math.Log10(hashrate)/3)))
+	if index >= len(hashrateUnits) {
+		index = len(hashrateUnits) - 1
+	}
+	return index
+}
+
 // HumaniseHashrate renders a hashrate into an appropriate SI-prefixed unit.
 func HumaniseHashrate(hashrate float64, opts ...HumanHashrateOption) HumanHashrate {
 	cfg := humanHashrateOptions{}
@@ -982,15 +2538,14 @@ func HumaniseHashrate(hashrate float64, opts ...HumanHashrateOption) HumanHashra
 			opt(&cfg)
 		}
 	}
-	if !isFinite(hashrate) || hashrate <= 0 {
-		return HumanHashrate{Value: 0, Unit: HashrateUnitHps, Display: "0 H/s", Exponent: 0}
+	sep := " "
+	if cfg.noSpace {
+		sep = ""
 	}
-	logValue := math.Log10(hashrate)
-	index := int(math.Max(0, math.Floor(logValue/3)))
-	if index >= len(hashrateUnits) {
-		index = len(hashrateUnits) - 1
+	if !isFinite(hashrate) || hashrate <= 0 {
+		return HumanHashrate{Value: 0, Unit: HashrateUnitHps, Display: "0" + sep + "H/s", Exponent: 0}
 	}
-	unit := hashrateUnits[index]
+	unit := hashrateUnits[hashrateUnitIndex(hashrate)]
 	scaled := hashrate / math.Pow(10, float64(unit.exponent*3))
 	if !isFinite(scaled) {
 		scaled = hashrate
@@ -999,13 +2554,13 @@ func HumaniseHashrate(hashrate float64, opts ...HumanHashrateOption) HumanHashra
 	var display string
 	switch {
 	case cfg.precision != nil:
-		display = fmt.Sprintf("%.*f %s", *cfg.precision, scaled, unit.unit)
+		display = fmt.Sprintf("%.*f%s%s", *cfg.precision, scaled, sep, unit.unit)
 	case scaled >= 100:
-		display = fmt.Sprintf("%.0f %s", scaled, unit.unit)
+		display = fmt.Sprintf("%.0f%s%s", scaled, sep, unit.unit)
 	case scaled >= 10:
-		display = fmt.Sprintf("%.1f %s", scaled, unit.unit)
+		display = fmt.Sprintf("%.1f%s%s", scaled, sep, unit.unit)
 	default:
-		display = fmt.Sprintf("%.2f %s", scaled, unit.unit)
+		display = fmt.Sprintf("%.2f%s%s", scaled, sep, unit.unit)
 	}
 	return HumanHashrate{
 		Value:    scaled,
@@ -1020,9 +2575,16 @@ type EstimateOption func(*estimateOptions)
 
 type estimateOptions struct {
 	multiplier           float64
+	multiplierSet        bool
 	quantile             *float64
 	primaryMode          PrimaryMode
 	probabilityPrecision int
+	minSeconds           float64
+	rigHashrate          *HashrateValue
+	humanOpts            []HumanHashrateOption
+	annotations          map[string]string
+	// err records an invalid option so EstimateNote can report it instead of silently ignoring it.
+	err error
 }
 
 func defaultEstimateOptions() estimateOptions {
@@ -1038,6 +2600,7 @@ func defaultEstimateOptions() estimateOptions {
 func WithEstimateMultiplier(multiplier float64) EstimateOption {
 	return func(cfg *estimateOptions) {
 		cfg.multiplier = multiplier
+		cfg.multiplierSet = true
 		cfg.quantile = nil
 	}
 }
@@ -1045,20 +2608,23 @@ func WithEstimateMultiplier(multiplier float64) EstimateOption {
 // WithEstimateReliability selects a preset reliability level.
 func WithEstimateReliability(id ReliabilityID) EstimateOption {
 	return func(cfg *estimateOptions) {
-		if lvl, ok := reliabilityLevels[id]; ok {
+		if lvl, err := getReliabilityLevel(id); err == nil {
 			cfg.multiplier = lvl.Multiplier
+			cfg.multiplierSet = true
 			cfg.quantile = lvl.Confidence
 		}
 	}
 }
 
-// WithEstimateConfidence configures a raw quantile in (0,1).
+// WithEstimateConfidence configures a raw quantile in (0,1); EstimateNote rejects values outside that range.
 func WithEstimateConfidence(confidence float64) EstimateOption {
 	return func(cfg *estimateOptions) {
-		if confidence <= 0 || confidence >= 1 {
+		if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+			cfg.err = fmt.Errorf("confidence must be in (0,1), got %v", confidence)
 			return
 		}
 		cfg.multiplier = -math.Log(1 - confidence)
+		cfg.multiplierSet = true
 		cfg.quantile = &confidence
 	}
 }
@@ -1083,6 +2649,39 @@ func WithEstimateProbabilityPrecision(precision int) EstimateOption {
 	}
 }
 
+// WithEstimateHashratePrecision fixes the decimal places of every humanised hashrate in the estimate.
+func WithEstimateHashratePrecision(precision int) EstimateOption {
+	return func(cfg *estimateOptions) {
+		cfg.humanOpts = append(cfg.humanOpts, WithHumanHashratePrecision(precision))
+	}
+}
+
+// WithEstimateClampWindow raises any window shorter than minSeconds up to minSeconds.
+// The window actually used is reported in BillEstimate.EffectiveSeconds.
+func WithEstimateClampWindow(minSeconds float64) EstimateOption {
+	return func(cfg *estimateOptions) {
+		if !isFinite(minSeconds) || minSeconds <= 0 {
+			return
+		}
+		cfg.minSeconds = minSeconds
+	}
+}
+
+// WithEstimateRigHashrate checks the estimate against a rig, filling BillEstimate.Feasible and Shortfall.
+func WithEstimateRigHashrate(hashrate HashrateValue) EstimateOption {
+	return func(cfg *estimateOptions) {
+		rig := hashrate
+		cfg.rigHashrate = &rig
+	}
+}
+
+// WithEstimateAnnotations attaches a copy of m to BillEstimate.Annotations.
+func WithEstimateAnnotations(m map[string]string) EstimateOption {
+	return func(cfg *estimateOptions) {
+		cfg.annotations = maps.Clone(m)
+	}
+}
+
 // EstimateNote computes a BillEstimate for the provided note and window.
 func EstimateNote(note any, seconds float64, opts ...EstimateOption) (BillEstimate, error) {
 	if !isFinite(seconds) || seconds <= 0 {
@@ -1098,9 +2697,25 @@ func EstimateNote(note any, seconds float64, opts ...EstimateOption) (BillEstima
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	if cfg.err != nil {
+		return BillEstimate{}, cfg.err
+	}
+	if !cfg.multiplierSet {
+		lvl := defaultReliabilityLevel()
+		cfg.multiplier = lvl.Multiplier
+		cfg.quantile = lvl.Confidence
+	}
 	if cfg.multiplier <= 0 {
 		return BillEstimate{}, errors.New("multiplier must be > 0")
 	}
+	effectiveSeconds := math.Max(seconds, cfg.minSeconds)
+	var rigHPS float64
+	if cfg.rigHashrate != nil {
+		rigHPS, err = NormalizeHashrateValue(*cfg.rigHashrate)
+		if err != nil {
+			return BillEstimate{}, fmt.Errorf("rig hashrate: %w", err)
+		}
+	}
 
 	probability, err := ProbabilityPerHash(resolved)
 	if err != nil {
@@ -1110,11 +2725,11 @@ func EstimateNote(note any, seconds float64, opts ...EstimateOption) (BillEstima
 	if err != nil {
 		return BillEstimate{}, err
 	}
-	meanRate, err := RequiredHashrateMean(resolved, seconds)
+	meanRate, err := RequiredHashrateMean(resolved, effectiveSeconds)
 	if err != nil {
 		return BillEstimate{}, err
 	}
-	quantileRate, err := RequiredHashrate(resolved, seconds, WithMultiplier(cfg.multiplier))
+	quantileRate, err := RequiredHashrate(resolved, effectiveSeconds, WithMultiplier(cfg.multiplier))
 	if err != nil {
 		return BillEstimate{}, err
 	}
@@ -1142,22 +2757,34 @@ func EstimateNote(note any, seconds float64, opts ...EstimateOption) (BillEstima
 		quantileCopy = &val
 	}
 
-	return BillEstimate{
-		Sharenote:                resolved,
-		Label:                    resolved.Label(),
-		ZBits:                    resolved.ZBits,
-		SecondsTarget:            seconds,
-		ProbabilityPerHash:       probability,
-		ProbabilityDisplay:       FormatProbabilityDisplay(resolved.ZBits, cfg.probabilityPrecision),
-		ExpectedHashes:           expectation.Float64(),
-		RequiredHashrateMean:     meanRate.Float64(),
-		RequiredHashrateQuantile: quantileRate.Float64(),
-		RequiredHashratePrimary:  primary.Float64(),
-		RequiredHashrateHuman:    primary.Human(),
-		Multiplier:               cfg.multiplier,
-		Quantile:                 quantileCopy,
-		PrimaryMode:              primaryMode,
-	}, nil
+	bill := BillEstimate{
+		Sharenote:                     resolved,
+		Label:                         resolved.Label(),
+		ZBits:                         resolved.ZBits,
+		SecondsTarget:                 seconds,
+		EffectiveSeconds:              effectiveSeconds,
+		ProbabilityPerHash:            probability,
+		ProbabilityDisplay:            FormatProbabilityDisplay(resolved.ZBits, cfg.probabilityPrecision),
+		ExpectedHashes:                expectation.Float64(),
+		RequiredHashrateMean:          meanRate.Float64(),
+		RequiredHashrateQuantile:      quantileRate.Float64(),
+		RequiredHashratePrimary:       primary.Float64(),
+		RequiredHashrateHuman:         primary.Human(cfg.humanOpts...),
+		RequiredHashrateMeanHuman:     meanRate.Human(cfg.humanOpts...),
+		RequiredHashrateQuantileHuman: quantileRate.Human(cfg.humanOpts...),
+		Multiplier:                    cfg.multiplier,
+		Quantile:                      quantileCopy,
+		PrimaryMode:                   primaryMode,
+		ReliabilityLabel:              NearestReliabilityLevel(cfg.multiplier).Label,
+	}
+	if cfg.rigHashrate != nil {
+		bill.Feasible = bill.MeetsHashrate(rigHPS)
+		bill.Shortfall = bill.HashrateShortfall(rigHPS)
+	}
+	if len(cfg.annotations) > 0 {
+		bill.Annotations = maps.Clone(cfg.annotations)
+	}
+	return bill, nil
 }
 
 // EstimateNotes estimates multiple notes at once.
@@ -1173,6 +2800,251 @@ func EstimateNotes(notes []any, seconds float64, opts ...EstimateOption) ([]Bill
 	return results, nil
 }
 
+// Estimator bundles a window and estimate options for repeated EstimateNote calls.
+type Estimator struct {
+	seconds float64
+	opts    []EstimateOption
+}
+
+// NewEstimator constructs an Estimator; seconds must be finite and > 0.
+func NewEstimator(seconds float64, opts ...EstimateOption) (Estimator, error) {
+	if !isFinite(seconds) || seconds <= 0 {
+		return Estimator{}, errors.New("seconds must be > 0")
+	}
+	return Estimator{seconds: seconds, opts: slices.Clone(opts)}, nil
+}
+
+// Note estimates a single note with the stored window and options.
+func (e Estimator) Note(note any) (BillEstimate, error) {
+	return EstimateNote(note, e.seconds, e.opts...)
+}
+
+// Notes estimates each note with the stored window and options.
+func (e Estimator) Notes(notes []any) ([]BillEstimate, error) {
+	return EstimateNotes(notes, e.seconds, e.opts...)
+}
+
+// EstimateUniqueNotes estimates each distinct label once, preserving first-seen order.
+// Unlike EstimateNotes, the result may be shorter than the input when labels repeat.
+func EstimateUniqueNotes(notes []any, seconds float64, opts ...EstimateOption) ([]BillEstimate, error) {
+	seen := make(map[string]struct{}, len(notes))
+	unique := make([]any, 0, len(notes))
+	for _, note := range notes {
+		resolved, err := EnsureNote(note)
+		if err != nil {
+			return nil, err
+		}
+		label := resolved.Label()
+		if _, ok := seen[label]; ok {
+			continue
+		}
+		seen[label] = struct{}{}
+		unique = append(unique, resolved)
+	}
+	return EstimateNotes(unique, seconds, opts...)
+}
+
+// StreamBillEstimatesJSON writes a JSON array of estimates to w, encoding each one as it is computed.
+// If a note fails to estimate, the array is closed before returning the error so the output stays valid JSON.
+func StreamBillEstimatesJSON(w io.Writer, notes []any, seconds float64, opts ...EstimateOption) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i, note := range notes {
+		estimate, err := EstimateNote(note, seconds, opts...)
+		if err != nil {
+			if _, werr := io.WriteString(w, "]"); werr != nil {
+				return werr
+			}
+			return fmt.Errorf("estimate note %d: %w", i, err)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(estimate); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// billEstimateCSVHeader lists the columns written by WriteBillEstimatesCSV. Human displays are
+// omitted because ReadBillEstimatesCSV recomputes them from the numeric columns.
+var billEstimateCSVHeader = []string{
+	"label",
+	"zbits",
+	"seconds_target",
+	"effective_seconds",
+	"probability_per_hash",
+	"probability_display",
+	"expected_hashes",
+	"required_hashrate_mean",
+	"required_hashrate_quantile",
+	"required_hashrate_primary",
+	"multiplier",
+	"quantile",
+	"primary_mode",
+}
+
+// WriteBillEstimatesCSV writes the estimates as CSV with a header row. Floats use the shortest
+// representation that round-trips exactly; a nil Quantile is written as an empty cell.
+func WriteBillEstimatesCSV(w io.Writer, estimates []BillEstimate) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(billEstimateCSVHeader); err != nil {
+		return err
+	}
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	for _, b := range estimates {
+		quantile := ""
+		if b.Quantile != nil {
+			quantile = formatFloat(*b.Quantile)
+		}
+		record := []string{
+			b.Label,
+			formatFloat(b.ZBits),
+			formatFloat(b.SecondsTarget),
+			formatFloat(b.EffectiveSeconds),
+			formatFloat(b.ProbabilityPerHash),
+			b.ProbabilityDisplay,
+			formatFloat(b.ExpectedHashes),
+			formatFloat(b.RequiredHashrateMean),
+			formatFloat(b.RequiredHashrateQuantile),
+			formatFloat(b.RequiredHashratePrimary),
+			formatFloat(b.Multiplier),
+			quantile,
+			string(b.PrimaryMode),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadBillEstimatesCSV reads estimates written by WriteBillEstimatesCSV. Only the label column is
+// required; missing optional columns leave their fields zero (EffectiveSeconds falls back to
+// SecondsTarget, ZBits to the label) and human displays are recomputed from the hashrates.
+func ReadBillEstimatesCSV(r io.Reader) ([]BillEstimate, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["label"]; !ok {
+		return nil, errors.New("csv header is missing the label column")
+	}
+
+	var estimates []BillEstimate
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return estimates, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv line %d: %w", line, err)
+		}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		var parseErr error
+		number := func(name string) float64 {
+			raw := cell(name)
+			if raw == "" || parseErr != nil {
+				return 0
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				parseErr = fmt.Errorf("line %d: parse %s: %w", line, name, err)
+			}
+			return v
+		}
+
+		note, err := parseLabel(cell("label"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if cell("zbits") != "" {
+			labelled := note
+			note, err = NoteFromZBits(number("zbits"))
+			if parseErr == nil && err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if parseErr == nil && (note.Z != labelled.Z || note.Cents != labelled.Cents) {
+				return nil, fmt.Errorf("line %d: zbits %s quantize to %s, not label %s", line, cell("zbits"), note.Label(), labelled.Label())
+			}
+		}
+		b := BillEstimate{
+			Sharenote:                note,
+			Label:                    note.Label(),
+			ZBits:                    note.ZBits,
+			SecondsTarget:            number("seconds_target"),
+			EffectiveSeconds:         number("effective_seconds"),
+			ProbabilityPerHash:       number("probability_per_hash"),
+			ProbabilityDisplay:       cell("probability_display"),
+			ExpectedHashes:           number("expected_hashes"),
+			RequiredHashrateMean:     number("required_hashrate_mean"),
+			RequiredHashrateQuantile: number("required_hashrate_quantile"),
+			RequiredHashratePrimary:  number("required_hashrate_primary"),
+			Multiplier:               number("multiplier"),
+			PrimaryMode:              PrimaryMode(cell("primary_mode")),
+		}
+		if cell("quantile") != "" {
+			quantile := number("quantile")
+			b.Quantile = &quantile
+		}
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if b.EffectiveSeconds == 0 {
+			b.EffectiveSeconds = b.SecondsTarget
+		}
+		b.RequiredHashrateHuman = HumaniseHashrate(b.RequiredHashratePrimary)
+		b.RequiredHashrateMeanHuman = HumaniseHashrate(b.RequiredHashrateMean)
+		b.RequiredHashrateQuantileHuman = HumaniseHashrate(b.RequiredHashrateQuantile)
+		estimates = append(estimates, b)
+	}
+}
+
+var prometheusMetricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteNoteHistogramPrometheus writes per-label note counts as a Prometheus text-format gauge,
+// one `name{label="33Z53"} 12` sample per entry in ascending key order.
+func WriteNoteHistogramPrometheus(w io.Writer, counts map[string]int, metricName string) error {
+	if !prometheusMetricNamePattern.MatchString(metricName) {
+		return fmt.Errorf("invalid prometheus metric name %q", metricName)
+	}
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n", metricName); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		if _, err := fmt.Fprintf(w, "%s{label=\"%s\"} %d\n", metricName, prometheusLabelEscaper.Replace(label), counts[label]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PlanOption configures plan execution for PlanSharenoteFromHashrate.
 type PlanOption func(*planOptions)
 
@@ -1228,6 +3100,19 @@ func PlanSharenoteFromHashrate(hashrate HashrateValue, seconds float64, opts ...
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	// Both halves of the plan fall back to the same SetDefaultReliability preset, so the note is
+	// picked at the multiplier the bill reports and HashrateMultiplier records what was used.
+	lvl := defaultReliabilityLevel()
+	if !resolveHashrateOptions(cfg.hashrateOpts).multiplierSet {
+		cfg.hashrateOpts = append(slices.Clip(cfg.hashrateOpts), WithMultiplier(lvl.Multiplier))
+	}
+	estimateCfg := defaultEstimateOptions()
+	for _, opt := range cfg.estimateOpts {
+		opt(&estimateCfg)
+	}
+	if !estimateCfg.multiplierSet {
+		cfg.estimateOpts = append(slices.Clip(cfg.estimateOpts), WithEstimateReliability(lvl.ID))
+	}
 
 	note, err := NoteFromHashrate(hashrate, seconds, cfg.hashrateOpts...)
 	if err != nil {
@@ -1239,12 +3124,97 @@ func PlanSharenoteFromHashrate(hashrate HashrateValue, seconds float64, opts ...
 		return SharenotePlan{}, err
 	}
 
+	hashrateCfg := resolveHashrateOptions(cfg.hashrateOpts)
 	return SharenotePlan{
 		Sharenote:          note,
 		Bill:               bill,
 		SecondsTarget:      seconds,
 		InputHashrateHPS:   numeric,
 		InputHashrateHuman: HumaniseHashrate(numeric),
+		HashrateMultiplier: hashrateCfg.multiplier,
+		Rounding:           hashrateCfg.rounding,
+	}, nil
+}
+
+const (
+	planTokenVersion = 1
+	// planTokenSize is a version byte, six float64s, and the rounding and primary-mode bytes.
+	planTokenSize = 1 + 6*8 + 2
+)
+
+var (
+	planRoundingCodes = []RoundingMode{"", RoundingFloor, RoundingNearest}
+	planPrimaryCodes  = []PrimaryMode{"", PrimaryModeMean, PrimaryModeQuantile}
+)
+
+// Encode packs the plan's note, window, input hashrate, reliability, and primary mode into a compact
+// base64url token. Display-only settings (e.g. probability precision) are not carried.
+func (p SharenotePlan) Encode() string {
+	buf := make([]byte, 0, planTokenSize)
+	buf = append(buf, planTokenVersion)
+	quantile := math.NaN()
+	if p.Bill.Quantile != nil {
+		quantile = *p.Bill.Quantile
+	}
+	for _, v := range []float64{p.Sharenote.ZBits, p.SecondsTarget, p.InputHashrateHPS, p.HashrateMultiplier, p.Bill.Multiplier, quantile} {
+		buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(v))
+	}
+	buf = append(buf, byte(max(0, slices.Index(planRoundingCodes, p.Rounding))))
+	buf = append(buf, byte(max(0, slices.Index(planPrimaryCodes, p.Bill.PrimaryMode))))
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodePlan rebuilds a plan from an Encode token, re-running EstimateNote for the bill.
+func DecodePlan(token string) (SharenotePlan, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return SharenotePlan{}, fmt.Errorf("decode plan token: %w", err)
+	}
+	if len(raw) != planTokenSize {
+		return SharenotePlan{}, fmt.Errorf("plan token must be %d bytes, got %d", planTokenSize, len(raw))
+	}
+	if raw[0] != planTokenVersion {
+		return SharenotePlan{}, fmt.Errorf("unsupported plan token version %d", raw[0])
+	}
+	values := make([]float64, 6)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.BigEndian.Uint64(raw[1+8*i:]))
+	}
+	zbits, seconds, hashrate, hashrateMultiplier, billMultiplier, quantile := values[0], values[1], values[2], values[3], values[4], values[5]
+	roundingCode, primaryCode := int(raw[planTokenSize-2]), int(raw[planTokenSize-1])
+	if roundingCode >= len(planRoundingCodes) || primaryCode >= len(planPrimaryCodes) {
+		return SharenotePlan{}, errors.New("plan token has an unknown mode")
+	}
+	if !isFinite(hashrate) || hashrate <= 0 {
+		return SharenotePlan{}, errors.New("hashrate must be > 0")
+	}
+	if !isFinite(hashrateMultiplier) || hashrateMultiplier <= 0 || !isFinite(billMultiplier) || billMultiplier <= 0 {
+		return SharenotePlan{}, errors.New("multiplier must be > 0")
+	}
+	note, err := NoteFromZBits(zbits)
+	if err != nil {
+		return SharenotePlan{}, err
+	}
+
+	estimateOpts := []EstimateOption{WithEstimateMultiplier(billMultiplier)}
+	if !math.IsNaN(quantile) {
+		estimateOpts = []EstimateOption{WithEstimateConfidence(quantile)}
+	}
+	if mode := planPrimaryCodes[primaryCode]; mode != "" {
+		estimateOpts = append(estimateOpts, WithEstimatePrimaryMode(mode))
+	}
+	bill, err := EstimateNote(note, seconds, estimateOpts...)
+	if err != nil {
+		return SharenotePlan{}, err
+	}
+	return SharenotePlan{
+		Sharenote:          note,
+		Bill:               bill,
+		SecondsTarget:      seconds,
+		InputHashrateHPS:   hashrate,
+		InputHashrateHuman: HumaniseHashrate(hashrate),
+		HashrateMultiplier: hashrateMultiplier,
+		Rounding:           planRoundingCodes[roundingCode],
 	}, nil
 }
 
@@ -1271,6 +3241,121 @@ func CombineNotesSerial(notes ...any) (Sharenote, error) {
 	return NoteFromZBits(zbits)
 }
 
+// CombinedTargetSerial returns the integer hash target of the serial combination of notes.
+func CombinedTargetSerial(notes ...any) (*big.Int, error) {
+	combined, err := CombineNotesSerial(notes...)
+	if err != nil {
+		return nil, err
+	}
+	return TargetFor(combined)
+}
+
+// SplitNoteSerial inverts CombineNotesSerial for equal parts, returning the note with log2(difficulty/parts).
+func SplitNoteSerial(note any, parts int) (Sharenote, error) {
+	if parts < 1 {
+		return Sharenote{}, errors.New("parts must be >= 1")
+	}
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		return Sharenote{}, err
+	}
+	zbits := resolved.ZBits - math.Log2(float64(parts))
+	if zbits < 0 {
+		return Sharenote{}, errors.New("split note would have negative zbits")
+	}
+	return NoteFromZBits(zbits)
+}
+
+// CombineNotesSerialBreakdown combines notes like CombineNotesSerial and also returns each input's
+// fractional share of the combined difficulty, aligned with the inputs.
+func CombineNotesSerialBreakdown(notes ...any) (Sharenote, []float64, error) {
+	if len(notes) == 0 {
+		return Sharenote{}, nil, errors.New("notes slice must not be empty")
+	}
+	resolved := make([]Sharenote, len(notes))
+	maxZBits := math.Inf(-1)
+	for i, note := range notes {
+		n, err := EnsureNote(note)
+		if err != nil {
+			return Sharenote{}, nil, err
+		}
+		resolved[i] = n
+		maxZBits = math.Max(maxZBits, n.ZBits)
+	}
+	combined, err := CombineNotesSerial(notes...)
+	if err != nil {
+		return Sharenote{}, nil, err
+	}
+	// Scale relative to the hardest note so shares stay finite even when raw difficulties overflow.
+	shares := make([]float64, len(resolved))
+	sum := 0.0
+	for i, n := range resolved {
+		shares[i] = math.Exp2(n.ZBits - maxZBits)
+		sum += shares[i]
+	}
+	for i := range shares {
+		shares[i] /= sum
+	}
+	return combined, shares, nil
+}
+
+// WorkAccumulator keeps a running serial combination of notes. The zero value is ready to use.
+type WorkAccumulator struct {
+	total float64
+	count int
+}
+
+// Add folds the note's difficulty (2^zbits) into the running total.
+func (w *WorkAccumulator) Add(note any) error {
+	diff, err := difficultyFromNote(note)
+	if err != nil {
+		return err
+	}
+	w.total += diff
+	w.count++
+	return nil
+}
+
+// TotalDifficulty returns the summed difficulty of every note added so far.
+func (w *WorkAccumulator) TotalDifficulty() float64 {
+	return w.total
+}
+
+// AsNote returns the note whose zbits equal log2 of the accumulated difficulty, matching CombineNotesSerial.
+func (w *WorkAccumulator) AsNote() (Sharenote, error) {
+	if w.count == 0 {
+		return Sharenote{}, errors.New("work accumulator is empty")
+	}
+	if !isFinite(w.total) || w.total <= 0 {
+		return NoteFromZBits(0)
+	}
+	zbits, err := zBitsFromDifficulty(w.total)
+	if err != nil {
+		return Sharenote{}, err
+	}
+	return NoteFromZBits(zbits)
+}
+
+// MeanNote returns the note whose Z-bit difficulty is the arithmetic mean of the inputs' difficulties.
+func MeanNote(notes []any) (Sharenote, error) {
+	if len(notes) == 0 {
+		return Sharenote{}, errors.New("notes slice must not be empty")
+	}
+	total := 0.0
+	for _, note := range notes {
+		diff, err := difficultyFromNote(note)
+		if err != nil {
+			return Sharenote{}, err
+		}
+		total += diff
+	}
+	zbits, err := zBitsFromDifficulty(total / float64(len(notes)))
+	if err != nil {
+		return Sharenote{}, err
+	}
+	return NoteFromZBits(zbits)
+}
+
 // NoteDifference subtracts subtrahend Z-bit difficulty from the minuend (clamped at zero).
 func NoteDifference(minuend, subtrahend any) (Sharenote, error) {
 	minDifficulty, err := difficultyFromNote(minuend)
@@ -1314,6 +3399,24 @@ func ScaleNote(note any, factor float64) (Sharenote, error) {
 	return NoteFromZBits(zbits)
 }
 
+// ScaleNoteToHashrate scales the note's difficulty so that minting it within seconds requires
+// targetHashrate, returning the scaled note and the factor applied (target / current requirement).
+func ScaleNoteToHashrate(note any, targetHashrate, seconds float64, opts ...HashrateOption) (Sharenote, float64, error) {
+	if !isFinite(targetHashrate) || targetHashrate <= 0 {
+		return Sharenote{}, 0, errors.New("target hashrate must be > 0")
+	}
+	current, err := requiredHashrateValue(note, seconds, opts...)
+	if err != nil {
+		return Sharenote{}, 0, err
+	}
+	factor := targetHashrate / current
+	scaled, err := ScaleNote(note, factor)
+	if err != nil {
+		return Sharenote{}, 0, err
+	}
+	return scaled, factor, nil
+}
+
 // DivideNotes returns the ratio of two note Z-bit difficulties.
 func DivideNotes(numerator, denominator any) (float64, error) {
 	numDifficulty, err := difficultyFromNote(numerator)
@@ -1330,36 +3433,110 @@ func DivideNotes(numerator, denominator any) (float64, error) {
 	return numDifficulty / denDifficulty, nil
 }
 
+// DivideNotesExact returns the difficulty ratio numerator/denominator exactly, as the ratio of their
+// integer targets TargetFor(denominator)/TargetFor(numerator) (a rarer note has a smaller target).
+func DivideNotesExact(numerator, denominator any) (*big.Rat, error) {
+	numTarget, err := TargetFor(numerator)
+	if err != nil {
+		return nil, err
+	}
+	denTarget, err := TargetFor(denominator)
+	if err != nil {
+		return nil, err
+	}
+	if numTarget.Sign() <= 0 || denTarget.Sign() <= 0 {
+		return nil, errors.New("division by zero target")
+	}
+	return new(big.Rat).SetFrac(denTarget, numTarget), nil
+}
+
+// EquivalentCount returns how many common notes match one rare note in difficulty, 2^(rare-common).
+// Working in zbits keeps the ratio exact for notes whose individual difficulties overflow float64.
+func EquivalentCount(rare, common any) (float64, error) {
+	rareNote, err := EnsureNote(rare)
+	if err != nil {
+		return 0, err
+	}
+	commonNote, err := EnsureNote(common)
+	if err != nil {
+		return 0, err
+	}
+	count := math.Exp2(rareNote.ZBits - commonNote.ZBits)
+	if !isFinite(count) {
+		return 0, errors.New("equivalent count is not finite")
+	}
+	return count, nil
+}
+
 // HashrateOption configures multiplier/reliability.
 type HashrateOption func(*hashrateOptions)
 
 type hashrateOptions struct {
-	multiplier float64
+	multiplier    float64
+	multiplierSet bool
+	rounding      RoundingMode
+	closedUpper   bool
+	// err records an invalid option so callers can report it instead of silently ignoring it.
+	err error
+}
+
+// resolveHashrateOptions applies opts over the mean (multiplier 1) defaults.
+func resolveHashrateOptions(opts []HashrateOption) hashrateOptions {
+	cfg := hashrateOptions{multiplier: 1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
 }
 
 // WithMultiplier sets the Poisson multiplier directly.
 func WithMultiplier(multiplier float64) HashrateOption {
 	return func(cfg *hashrateOptions) {
 		cfg.multiplier = multiplier
+		cfg.multiplierSet = true
+	}
+}
+
+// WithNoteRounding selects how NoteFromHashrate snaps derived Z-bits onto cent-Z labels (default RoundingFloor).
+func WithNoteRounding(mode RoundingMode) HashrateOption {
+	return func(cfg *hashrateOptions) {
+		switch mode {
+		case RoundingFloor, RoundingNearest:
+			cfg.rounding = mode
+		}
+	}
+}
+
+// WithRangeClosedUpper makes HashrateRangeForNote return an inclusive Max: the largest hashrate
+// (within a relative 1e-9) that still maps to the note, rather than the exclusive next-step boundary.
+func WithRangeClosedUpper() HashrateOption {
+	return func(cfg *hashrateOptions) {
+		cfg.closedUpper = true
 	}
 }
 
 // WithReliability selects one of the named presets or a custom confidence (0,1).
 func WithReliability(id ReliabilityID) HashrateOption {
 	return func(cfg *hashrateOptions) {
-		if lvl, ok := reliabilityLevels[id]; ok {
+		if lvl, err := getReliabilityLevel(id); err == nil {
 			cfg.multiplier = lvl.Multiplier
+			cfg.multiplierSet = true
 		}
 	}
 }
 
-// WithConfidence configures a Poisson multiplier from a raw confidence between 0 and 1.
+// WithConfidence configures a Poisson multiplier from a raw confidence in (0,1); values outside that
+// range are reported as an error by the function the option is passed to.
 func WithConfidence(confidence float64) HashrateOption {
 	return func(cfg *hashrateOptions) {
-		if confidence <= 0 || confidence >= 1 {
+		if !isFinite(confidence) || confidence <= 0 || confidence >= 1 {
+			cfg.err = fmt.Errorf("confidence must be in (0,1), got %v", confidence)
 			return
 		}
 		cfg.multiplier = -math.Log(1 - confidence)
+		cfg.multiplierSet = true
 	}
 }
 
@@ -0,0 +1,93 @@
+//go:build cbor
+
+package snip00
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	cborFloat64     = 0xfb
+	cborTextMajor   = 0x60
+	cborMajorMask   = 0xe0
+	cborInfoMask    = 0x1f
+	cborInfoUint8   = 24
+	cborInfoUint16  = 25
+	cborInfoUint32  = 26
+	cborFloat64Size = 9
+)
+
+// MarshalCBOR encodes the receiver's ZBits as a CBOR double, preserving full precision.
+func (n Sharenote) MarshalCBOR() ([]byte, error) {
+	if !isFinite(n.ZBits) {
+		return nil, errors.New("zbits must be finite")
+	}
+	buf := make([]byte, cborFloat64Size)
+	buf[0] = cborFloat64
+	binary.BigEndian.PutUint64(buf[1:], math.Float64bits(n.ZBits))
+	return buf, nil
+}
+
+// UnmarshalCBOR decodes a CBOR double (zbits) or a CBOR text string (label).
+func (n *Sharenote) UnmarshalCBOR(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty cbor sharenote")
+	}
+	var (
+		note Sharenote
+		err  error
+	)
+	switch {
+	case data[0] == cborFloat64:
+		if len(data) != cborFloat64Size {
+			return fmt.Errorf("cbor float64 must be %d bytes, got %d", cborFloat64Size, len(data))
+		}
+		note, err = NoteFromZBits(math.Float64frombits(binary.BigEndian.Uint64(data[1:])))
+	case data[0]&cborMajorMask == cborTextMajor:
+		var label string
+		label, err = decodeCBORText(data)
+		if err == nil {
+			note, err = parseLabel(label)
+		}
+	default:
+		return fmt.Errorf("unsupported cbor initial byte 0x%02x", data[0])
+	}
+	if err != nil {
+		return err
+	}
+	*n = note
+	return nil
+}
+
+func decodeCBORText(data []byte) (string, error) {
+	info := data[0] & cborInfoMask
+	length, offset := uint64(info), 1
+	switch info {
+	case cborInfoUint8:
+		if len(data) < 2 {
+			return "", errors.New("truncated cbor text length")
+		}
+		length, offset = uint64(data[1]), 2
+	case cborInfoUint16:
+		if len(data) < 3 {
+			return "", errors.New("truncated cbor text length")
+		}
+		length, offset = uint64(binary.BigEndian.Uint16(data[1:3])), 3
+	case cborInfoUint32:
+		if len(data) < 5 {
+			return "", errors.New("truncated cbor text length")
+		}
+		length, offset = uint64(binary.BigEndian.Uint32(data[1:5])), 5
+	default:
+		if info > cborInfoUint8 {
+			return "", fmt.Errorf("unsupported cbor text length encoding %d", info)
+		}
+	}
+	if uint64(len(data)-offset) != length {
+		return "", fmt.Errorf("cbor text length mismatch: header %d, payload %d", length, len(data)-offset)
+	}
+	return string(data[offset:]), nil
+}
@@ -0,0 +1,44 @@
+//go:build cbor
+
+package snip00
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSharenoteCBORRoundTrip(t *testing.T) {
+	note := MustNoteFromZBits(33.537812)
+	data, err := note.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR: %v", err)
+	}
+	if len(data) != 9 || data[0] != 0xfb {
+		t.Fatalf("unexpected encoding: %x", data)
+	}
+	var decoded Sharenote
+	if err := decoded.UnmarshalCBOR(data); err != nil {
+		t.Fatalf("UnmarshalCBOR: %v", err)
+	}
+	if decoded.ZBits != note.ZBits {
+		t.Fatalf("precision lost: got %.15f want %.15f", decoded.ZBits, note.ZBits)
+	}
+	if decoded.Label() != "33Z53" {
+		t.Fatalf("unexpected label: %s", decoded.Label())
+	}
+
+	text := append([]byte{0x65}, []byte("33Z53")...)
+	if err := decoded.UnmarshalCBOR(text); err != nil {
+		t.Fatalf("UnmarshalCBOR text: %v", err)
+	}
+	if decoded.Label() != "33Z53" {
+		t.Fatalf("unexpected label from text: %s", decoded.Label())
+	}
+
+	if err := decoded.UnmarshalCBOR(bytes.Repeat([]byte{0xfb}, 4)); err == nil {
+		t.Fatal("expected error for truncated float")
+	}
+	if err := decoded.UnmarshalCBOR([]byte{0x01}); err == nil {
+		t.Fatal("expected error for unsupported major type")
+	}
+}
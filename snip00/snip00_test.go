@@ -1,14 +1,18 @@
 package snip00
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -94,6 +98,220 @@ func TestNoteFromZBitsPreservesPrecision(t *testing.T) {
 	}
 }
 
+func TestQuantize(t *testing.T) {
+	note := MustNoteFromZBits(33.537812)
+	quantized := note.Quantize()
+	if quantized.ZBits != 33.53 {
+		t.Fatalf("expected zbits exactly 33.53, got %.12f", quantized.ZBits)
+	}
+	if quantized.Label() != note.Label() {
+		t.Fatalf("label changed: %s vs %s", quantized.Label(), note.Label())
+	}
+	if note.ZBits != 33.537812 {
+		t.Fatalf("receiver mutated: %.12f", note.ZBits)
+	}
+}
+
+func TestBoundingNotes(t *testing.T) {
+	lower, upper, err := BoundingNotes(33.535)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lower.Label() != "33Z53" || upper.Label() != "33Z54" {
+		t.Fatalf("unexpected bounds: %s, %s", lower, upper)
+	}
+	lower, upper, err = BoundingNotes(33.53)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lower.Label() != "33Z53" || upper.Label() != "33Z53" {
+		t.Fatalf("expected identical bounds on the grid, got %s, %s", lower, upper)
+	}
+	if _, _, err := BoundingNotes(-1); err == nil {
+		t.Fatal("expected error for negative zbits")
+	}
+	if _, _, err := BoundingNotes(math.NaN()); err == nil {
+		t.Fatal("expected error for NaN zbits")
+	}
+}
+
+func TestFrac(t *testing.T) {
+	if got := MustNoteFromZBits(33.537812).Frac(); math.Abs(got-0.007812) > 1e-9 {
+		t.Fatalf("unexpected frac: %.12f", got)
+	}
+	if got := mustParseLabel("33Z53").Frac(); math.Abs(got) > 1e-12 {
+		t.Fatalf("expected ~0 frac for grid note, got %.12f", got)
+	}
+}
+
+func TestSharenoteEqual(t *testing.T) {
+	plain := mustParseLabel("33Z53")
+	renamed := plain
+	renamed.labelOverride = "my-rig"
+	if !plain.Equal(renamed) {
+		t.Fatal("Equal should ignore label overrides")
+	}
+	if plain.EqualStrict(renamed) {
+		t.Fatal("EqualStrict should compare label overrides")
+	}
+	if !renamed.EqualStrict(renamed) {
+		t.Fatal("EqualStrict should hold for identical notes")
+	}
+	if plain.Equal(mustParseLabel("33Z54")) {
+		t.Fatal("different notes should not be Equal")
+	}
+}
+
+func TestWithZBitsKeepsLabelOverride(t *testing.T) {
+	renamed := mustParseLabel("33Z53")
+	renamed.labelOverride = "my-rig"
+	updated, err := renamed.WithZBits(34.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Label() != "my-rig" {
+		t.Fatalf("label override lost: %q", updated.Label())
+	}
+	if updated.Z != 34 || updated.Cents != 20 || !roughlyEqual(updated.ZBits, 34.2) {
+		t.Fatalf("unexpected components: %+v", updated)
+	}
+	if _, err := renamed.WithZBits(-1); err == nil {
+		t.Fatal("expected error for negative zbits")
+	}
+}
+
+func TestNextPrev(t *testing.T) {
+	for label, want := range map[string]string{"33Z99": "34Z00", "33Z53": "33Z54"} {
+		next, err := mustParseLabel(label).Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Label() != want {
+			t.Fatalf("%s.Next() = %s, want %s", label, next.Label(), want)
+		}
+	}
+	ceiling := MustNoteFromCentZBits(0)
+	ceiling.Z, ceiling.Cents = math.MaxInt, MaxCentZ
+	if _, err := ceiling.Next(); err == nil {
+		t.Fatal("expected error for Next at the int ceiling")
+	}
+	prev, err := mustParseLabel("34Z00").Prev()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev.Label() != "33Z99" {
+		t.Fatalf("34Z00.Prev() = %s, want 33Z99", prev.Label())
+	}
+	if _, err := mustParseLabel("0Z00").Prev(); err == nil {
+		t.Fatal("expected error stepping below 0Z00")
+	}
+}
+
+func TestNoteFromZInteger(t *testing.T) {
+	got, err := NoteFromZInteger(33)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NoteFromComponents(33, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want || got.Label() != "33Z00" {
+		t.Fatalf("NoteFromZInteger(33) = %+v, want %+v", got, want)
+	}
+	if _, err := NoteFromZInteger(-1); err == nil {
+		t.Fatal("expected error for negative z")
+	}
+}
+
+func TestCentZFloorBoundaries(t *testing.T) {
+	cases := []struct {
+		zbits float64
+		want  string
+	}{
+		{33.53, "33Z53"},
+		{33 + 0.53, "33Z53"},
+		{33.5299999999, "33Z52"},
+		{33.530000001, "33Z53"},
+		{33.5399999999, "33Z53"},
+		{33.99999999999, "33Z99"},
+		{34, "34Z00"},
+	}
+	for _, tc := range cases {
+		note, err := NoteFromZBits(tc.zbits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if note.Label() != tc.want {
+			t.Fatalf("NoteFromZBits(%.12f) = %s, want %s", tc.zbits, note.Label(), tc.want)
+		}
+	}
+
+	saved := CentZFloorEpsilon
+	t.Cleanup(func() { CentZFloorEpsilon = saved })
+	CentZFloorEpsilon = 1e-6
+	if note := MustNoteFromZBits(33.5299999999); note.Label() != "33Z53" {
+		t.Fatalf("wider epsilon should absorb the gap, got %s", note.Label())
+	}
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	note, err := NoteForLeadingZeroBits(33)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Label() != "33Z00" {
+		t.Fatalf("NoteForLeadingZeroBits(33) = %s", note)
+	}
+	if bits, err := LeadingZeroBits(note); err != nil || bits != 33 {
+		t.Fatalf("LeadingZeroBits = %d, %v", bits, err)
+	}
+	if bits, err := LeadingZeroBits("33Z99"); err != nil || bits != 33 {
+		t.Fatalf("LeadingZeroBits(33Z99) = %d, %v", bits, err)
+	}
+	target, err := TargetFor(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Cmp(new(big.Int).Lsh(big.NewInt(1), 256-33)) != 0 {
+		t.Fatalf("unexpected target: %s", target.Text(16))
+	}
+	for _, n := range []int{-1, 257} {
+		if _, err := NoteForLeadingZeroBits(n); err == nil {
+			t.Fatalf("expected error for %d", n)
+		}
+	}
+}
+
+func TestNoteFromLeadingZeros(t *testing.T) {
+	note, err := NoteFromLeadingZeros(40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Label() != "40Z00" {
+		t.Fatalf("NoteFromLeadingZeros(40) = %s", note)
+	}
+	if _, err := NoteFromLeadingZeros(300); err == nil {
+		t.Fatal("expected error for more than 256 bits")
+	}
+}
+
+func TestNoteFromComponentsStrict(t *testing.T) {
+	note, err := NoteFromComponentsStrict(33, 53)
+	if err != nil || note.Label() != "33Z53" {
+		t.Fatalf("NoteFromComponentsStrict(33, 53) = %v, %v", note, err)
+	}
+	for _, tc := range [][2]int{{33, 153}, {33, -1}, {-1, 0}} {
+		if _, err := NoteFromComponentsStrict(tc[0], tc[1]); err == nil {
+			t.Fatalf("expected error for %v", tc)
+		}
+	}
+	lenient, err := NoteFromComponents(33, 153)
+	if err != nil || lenient.Label() != "33Z99" {
+		t.Fatalf("NoteFromComponents(33, 153) = %v, %v; want clamped 33Z99", lenient, err)
+	}
+}
+
 func TestParseLabelVariants(t *testing.T) {
 	for _, label := range []string{"33Z53", "33Z 53CZ", "33.53Z"} {
 		if _, err := parseLabel(label); err != nil {
@@ -103,6 +321,153 @@ func TestParseLabelVariants(t *testing.T) {
 	if note, err := parseLabel("33z"); err != nil || note.Cents != 0 {
 		t.Fatalf("parseLabel lower-case: %+v, %v", note, err)
 	}
+	for _, label := range []string{"1e20Z", "99999999999999999999Z", "3000000000Z00"} {
+		if _, err := parseLabel(label); err == nil {
+			t.Fatalf("expected out-of-range error for %q", label)
+		}
+	}
+}
+
+func TestParseLabelCentZSuffixVariants(t *testing.T) {
+	cases := map[string]string{
+		"33Z53":     "33Z53",
+		"33Z53CZ":   "33Z53",
+		"33Z 53 CZ": "33Z53",
+		"33z53cz":   "33Z53",
+		"33Z5":      "33Z05",
+		"33Z5CZ":    "33Z05",
+		"33Z 5 CZ":  "33Z05",
+	}
+	for input, want := range cases {
+		note, err := parseLabel(input)
+		if err != nil {
+			t.Fatalf("parseLabel(%q): %v", input, err)
+		}
+		if note.Label() != want {
+			t.Fatalf("parseLabel(%q) = %s, want %s", input, note.Label(), want)
+		}
+	}
+	for _, input := range []string{"33ZCZ", "33Z123CZ", "33Z5C"} {
+		if _, err := parseLabel(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseLabelUnicode(t *testing.T) {
+	for _, input := range []string{
+		"33\u00A0Z\u00A053",
+		"33\uFF3A53",
+		"\uFF13\uFF13\uFF3A\uFF15\uFF13",
+		"33Z\u300053\uFF23\uFF3A",
+	} {
+		note, err := parseLabel(input)
+		if err != nil {
+			t.Fatalf("parseLabel(%q): %v", input, err)
+		}
+		if note.Label() != "33Z53" {
+			t.Fatalf("parseLabel(%q) = %s, want 33Z53", input, note.Label())
+		}
+	}
+	if _, err := parseLabel("33\u2013Z53"); err == nil {
+		t.Fatal("expected error for dash inside a label")
+	}
+}
+
+func TestNoteZLimit(t *testing.T) {
+	for _, label := range []string{"21474836Z", "2147483648Z00", "21474836.5Z", "3e9Z"} {
+		if _, err := parseLabel(label); err == nil {
+			t.Fatalf("expected %q to be rejected", label)
+		}
+	}
+	if _, err := parseLabel("21474835Z99"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NoteFromComponents(maxLabelZ+1, 0); err == nil {
+		t.Fatal("expected NoteFromComponents to reject z above the limit")
+	}
+	for _, zbits := range []float64{float64(maxLabelZ + 1), 1e20} {
+		if _, err := NoteFromZBits(zbits); err == nil {
+			t.Fatalf("expected NoteFromZBits(%g) to be rejected", zbits)
+		}
+	}
+	if _, err := EnsureNote(1e20); err == nil {
+		t.Fatal("expected EnsureNote to reject out-of-range zbits")
+	}
+}
+
+// FuzzParseLabel checks that parsing is idempotent under canonicalization: any label that parses
+// must re-parse from its canonical Label() to the same Z and cents, and its zbits must lie within
+// that label's cent-Z step.
+func FuzzParseLabel(f *testing.F) {
+	for _, seed := range []string{"33Z53", "33Z 53CZ", "33.53Z", "33.5Z", "33Z5CZ", "3.353e1Z", "0Z", "Z", "1e20Z", "99999999999999999999Z"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		note, err := parseLabel(input)
+		if err != nil {
+			return
+		}
+		reparsed, err := parseLabel(note.Label())
+		if err != nil {
+			t.Fatalf("parseLabel(%q) = %s, which does not re-parse: %v", input, note.Label(), err)
+		}
+		if reparsed.Z != note.Z || reparsed.Cents != note.Cents || reparsed.Label() != note.Label() {
+			t.Fatalf("parseLabel(%q) = %s, re-parsed as %s", input, note.Label(), reparsed.Label())
+		}
+		if diff := note.ZBits - reparsed.ZBits; diff < -1e-9 || diff >= CentZBitStep+1e-9 {
+			t.Fatalf("parseLabel(%q) = %s carries zbits %v outside its label", input, note.Label(), note.ZBits)
+		}
+	})
+}
+
+func TestNormalizeLabel(t *testing.T) {
+	for _, input := range []string{"33Z53", "33z53", " 33Z53 ", "33.53Z", "33Z 53CZ", "33Z53CZ", "33.530Z", "3.353e1Z", "33\u00A0Z53"} {
+		got, err := NormalizeLabel(input)
+		if err != nil {
+			t.Fatalf("NormalizeLabel(%q): %v", input, err)
+		}
+		if got != "33Z53" {
+			t.Fatalf("NormalizeLabel(%q) = %s, want 33Z53", input, got)
+		}
+	}
+	if _, err := NormalizeLabel("not a label"); err == nil {
+		t.Fatal("expected error for invalid label")
+	}
+}
+
+func TestParseLabelZeroForms(t *testing.T) {
+	for _, label := range []string{"Z", "z", " Z ", "0Z", "0Z00", "0.0Z", "0Z 00CZ"} {
+		note, err := parseLabel(label)
+		if err != nil {
+			t.Fatalf("parseLabel(%q): %v", label, err)
+		}
+		if note.Label() != "0Z00" || note.ZBits != 0 {
+			t.Fatalf("parseLabel(%q) = %s (%f), want 0Z00", label, note.Label(), note.ZBits)
+		}
+	}
+	for _, label := range []string{"", "   "} {
+		if _, err := parseLabel(label); err == nil {
+			t.Fatalf("expected error for empty label %q", label)
+		}
+	}
+}
+
+func TestParseLabelScientific(t *testing.T) {
+	for _, label := range []string{"3.353e1Z", "3353E-2Z"} {
+		note, err := parseLabel(label)
+		if err != nil {
+			t.Fatalf("parseLabel(%q): %v", label, err)
+		}
+		if note.Label() != "33Z53" || !roughlyEqual(note.ZBits, 33.53) {
+			t.Fatalf("parseLabel(%q) = %s (%f), want 33Z53", label, note.Label(), note.ZBits)
+		}
+	}
+	for _, label := range []string{"1e Z", "1eZ", "e1Z"} {
+		if _, err := parseLabel(label); err == nil {
+			t.Fatalf("expected error for %q", label)
+		}
+	}
 }
 
 func TestProbabilityMath(t *testing.T) {
@@ -123,6 +488,93 @@ func TestProbabilityMath(t *testing.T) {
 	}
 }
 
+func TestExpectedRewardPerHash(t *testing.T) {
+	ev, err := ExpectedRewardPerHash([]RewardEntry{
+		{Note: "1Z00", Reward: 10},
+		{Note: "2Z00", Reward: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(ev, 10*0.5+100*0.25) {
+		t.Fatalf("unexpected expected reward: %f", ev)
+	}
+	if _, err := ExpectedRewardPerHash([]RewardEntry{{Note: "1Z00", Reward: math.Inf(1)}}); err == nil {
+		t.Fatal("expected error for non-finite reward")
+	}
+}
+
+func TestTierTable(t *testing.T) {
+	table, err := NewTierTable(
+		TierBreakpoint{MaxZBits: 20, Name: "Bronze"},
+		TierBreakpoint{MaxZBits: 40, Name: "Silver"},
+		TierBreakpoint{MaxZBits: math.Inf(1), Name: "Gold"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[any]string{
+		"10Z00": "Bronze",
+		"20Z00": "Bronze",
+		"20Z01": "Silver",
+		"40Z00": "Silver",
+		"55Z00": "Gold",
+	}
+	for note, want := range cases {
+		got, err := table.Tier(note)
+		if err != nil {
+			t.Fatalf("Tier(%v): %v", note, err)
+		}
+		if got != want {
+			t.Fatalf("Tier(%v) = %s, want %s", note, got, want)
+		}
+	}
+	capped, err := NewTierTable(TierBreakpoint{MaxZBits: 20, Name: "Bronze"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := capped.Tier("21Z00"); err == nil {
+		t.Fatal("expected error above the highest tier")
+	}
+	if _, err := NewTierTable(
+		TierBreakpoint{MaxZBits: 40, Name: "Silver"},
+		TierBreakpoint{MaxZBits: 20, Name: "Bronze"},
+	); err == nil {
+		t.Fatal("expected error for descending breakpoints")
+	}
+}
+
+func TestRewardCurve(t *testing.T) {
+	curve, err := NewRewardCurve(2, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reward, err := curve.Reward("2Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(reward, 2*1.5*1.5) {
+		t.Fatalf("unexpected reward: %f", reward)
+	}
+	prev := 0.0
+	for _, label := range []string{"1Z00", "10Z50", "33Z53", "33Z54"} {
+		r, err := curve.Reward(label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r <= prev {
+			t.Fatalf("expected increasing rewards, %s gave %f after %f", label, r, prev)
+		}
+		prev = r
+	}
+	if _, err := NewRewardCurve(0, 2); err == nil {
+		t.Fatal("expected error for zero base")
+	}
+	if _, err := NewRewardCurve(1, -2); err == nil {
+		t.Fatal("expected error for negative growth")
+	}
+}
+
 func TestHashesMeasurementString(t *testing.T) {
 	cases := []struct {
 		value float64
@@ -145,6 +597,46 @@ func TestHashesMeasurementString(t *testing.T) {
 	}
 }
 
+func TestSortHashrates(t *testing.T) {
+	a := HashrateMeasurement{Value: 1e9}
+	b := HashrateMeasurement{Value: 5e9}
+	nan := HashrateMeasurement{Value: math.NaN()}
+	if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+		t.Fatal("unexpected ordering between finite measurements")
+	}
+	if nan.Compare(b) != 1 || b.Compare(nan) != -1 || nan.Compare(nan) != 0 {
+		t.Fatal("expected NaN to order after finite measurements")
+	}
+
+	ms := []HashrateMeasurement{b, nan, {Value: 3e9}, a}
+	SortHashrates(ms)
+	for i, want := range []float64{1e9, 3e9, 5e9} {
+		if ms[i].Value != want {
+			t.Fatalf("index %d: got %f want %f", i, ms[i].Value, want)
+		}
+	}
+	if !math.IsNaN(ms[3].Value) {
+		t.Fatalf("expected NaN last, got %f", ms[3].Value)
+	}
+}
+
+func TestHashesMeasurementHuman(t *testing.T) {
+	human := HashesMeasurement{Value: 12_340_000}.Human()
+	if human.Prefix != "M" || human.Exponent != 2 {
+		t.Fatalf("unexpected prefix/exponent: %q/%d", human.Prefix, human.Exponent)
+	}
+	if !roughlyEqual(human.Value, 12.34) {
+		t.Fatalf("unexpected scaled value: %f", human.Value)
+	}
+	if human.Display != "12.3 MH/s" || human.String() != human.Display {
+		t.Fatalf("unexpected display: %s", human.Display)
+	}
+	zero := HashesMeasurement{}.Human()
+	if zero.Display != "0 hashes" || zero.Value != 0 {
+		t.Fatalf("unexpected zero human: %+v", zero)
+	}
+}
+
 func TestHashrateRequirements(t *testing.T) {
 	note := mustParseLabel("33Z53")
 	mean, err := RequiredHashrateMean(note, 5)
@@ -173,460 +665,1915 @@ func TestNoteFromHashrate(t *testing.T) {
 	}
 }
 
-func TestHashrateRangeForNote(t *testing.T) {
+func TestNoteFromHashrateRounding(t *testing.T) {
 	const seconds = 5.0
-	const input = 1e12
-	note, err := NoteFromHashrate(HashrateValue{Value: input, Unit: HashrateUnitHps}, seconds)
+	rig := HashrateValue{Value: math.Exp2(33.538) / seconds, Unit: HashrateUnitHps}
+
+	floored, err := NoteFromHashrate(rig, seconds)
 	if err != nil {
 		t.Fatal(err)
 	}
-	rng, err := HashrateRangeForNote(note, seconds)
+	if floored.Label() != "33Z53" {
+		t.Fatalf("expected default floor to 33Z53, got %s", floored.Label())
+	}
+	explicit, err := NoteFromHashrate(rig, seconds, WithNoteRounding(RoundingFloor))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if rng.Min > input || rng.Max <= input {
-		t.Fatalf("range [%f, %f) does not contain %f", rng.Min, rng.Max, input)
+	if explicit.Label() != floored.Label() {
+		t.Fatalf("explicit floor mismatch: %s vs %s", explicit.Label(), floored.Label())
 	}
-	lowNote, err := NoteFromHashrate(HashrateValue{Value: rng.Min, Unit: HashrateUnitHps}, seconds)
+	nearest, err := NoteFromHashrate(rig, seconds, WithNoteRounding(RoundingNearest))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if lowNote.Label() != note.Label() {
-		t.Fatalf("expected min bound to map to %s, got %s", note.Label(), lowNote.Label())
+	if nearest.Label() != "33Z54" {
+		t.Fatalf("expected nearest rounding to 33Z54, got %s", nearest.Label())
+	}
+
+	plan, err := PlanSharenoteFromHashrate(rig, seconds, WithPlanHashrateOptions(WithNoteRounding(RoundingNearest)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Sharenote.Label() != "33Z54" {
+		t.Fatalf("expected plan to honour rounding, got %s", plan.Sharenote.Label())
 	}
 }
 
-func TestHashrateRangeReliabilityScaling(t *testing.T) {
-	note := mustParseLabel("33Z53")
-	base, err := HashrateRangeForNote(note, 5)
+func TestMaxNoteForHashrate(t *testing.T) {
+	rig := HashrateValue{Value: 5, Unit: HashrateUnitGHps}
+	mean, err := MaxNoteForHashrate(rig, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	often, err := HashrateRangeForNote(note, 5, WithReliability(ReliabilityOften95))
+	direct, err := NoteFromHashrate(rig, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if often.Min <= base.Min {
-		t.Fatalf("expected reliability range min > base min, got %f <= %f", often.Min, base.Min)
+	if mean.Label() != direct.Label() {
+		t.Fatalf("expected %s, got %s", direct.Label(), mean.Label())
 	}
-	if often.Max <= base.Max {
-		t.Fatalf("expected reliability range max > base max, got %f <= %f", often.Max, base.Max)
+	reliable, err := MaxNoteForHashrate(rig, 5, WithReliability(ReliabilityVeryLikely99))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp, _ := CompareNotes(reliable, mean); cmp >= 0 {
+		t.Fatalf("expected reliable note %s easier than mean note %s", reliable.Label(), mean.Label())
 	}
 }
 
-func TestParseHashrate(t *testing.T) {
-	value, err := ParseHashrate("5 GH/s")
+func TestHashrateRangeForNote(t *testing.T) {
+	const seconds = 5.0
+	const input = 1e12
+	note, err := NoteFromHashrate(HashrateValue{Value: input, Unit: HashrateUnitHps}, seconds)
 	if err != nil {
-		t.Fatalf("ParseHashrate: %v", err)
-	}
-	if !roughlyEqual(value, 5e9) {
-		t.Fatalf("unexpected parsed value: %f", value)
+		t.Fatal(err)
 	}
-	value, err = ParseHashrate("12.5 MH/s")
+	rng, err := HashrateRangeForNote(note, seconds)
 	if err != nil {
-		t.Fatalf("ParseHashrate words: %v", err)
-	}
-	if !roughlyEqual(value, 12.5e6) {
-		t.Fatalf("unexpected parsed value: %f", value)
+		t.Fatal(err)
 	}
-	if _, err := ParseHashrate("12 foo/s"); err == nil {
-		t.Fatal("expected error for invalid unit")
+	if rng.Min > input || rng.Max <= input {
+		t.Fatalf("range [%f, %f) does not contain %f", rng.Min, rng.Max, input)
 	}
-}
-
-func TestTargetFor(t *testing.T) {
-	target, err := TargetFor("33Z00")
+	lowNote, err := NoteFromHashrate(HashrateValue{Value: rng.Min, Unit: HashrateUnitHps}, seconds)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if target.BitLen() < 222 || target.BitLen() > 224 {
-		t.Fatalf("unexpected bit length %d", target.BitLen())
+	if lowNote.Label() != note.Label() {
+		t.Fatalf("expected min bound to map to %s, got %s", note.Label(), lowNote.Label())
 	}
 }
 
-func TestCompareNotes(t *testing.T) {
-	cmp, err := CompareNotes("32Z00", "33Z00")
+func TestHashrateRangeForZBand(t *testing.T) {
+	band, err := HashrateRangeForZBand(33, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if cmp >= 0 {
-		t.Fatal("expected 32Z00 < 33Z00")
+	mid, err := HashrateRangeForNote(mustParseLabel("33Z50"), 5)
+	if err != nil {
+		t.Fatal(err)
 	}
-	cmp, err = CompareNotes("33Z54", "33Z53")
+	if band.Min > mid.Min || band.Max < mid.Max {
+		t.Fatalf("band %+v does not contain 33Z50 range %+v", band, mid)
+	}
+	next, err := HashrateRangeForNote(mustParseLabel("34Z00"), 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if cmp <= 0 {
-		t.Fatal("expected 33Z54 > 33Z53")
+	if !roughlyEqual(band.Max, next.Min) {
+		t.Fatalf("band max %f should meet 34Z00 min %f", band.Max, next.Min)
+	}
+	if _, err := HashrateRangeForZBand(-1, 5); err == nil {
+		t.Fatal("expected error for negative z")
 	}
 }
 
-func TestNBitsConversion(t *testing.T) {
-	note, err := NBitsToSharenote("19752b59")
+func TestHashrateRangeJSON(t *testing.T) {
+	r := HashrateRange{Min: 1.5e9, Max: 2.25e9}
+	encoded, err := json.Marshal(r)
 	if err != nil {
 		t.Fatal(err)
 	}
-	value, _ := strconv.ParseUint("19752b59", 16, 32)
-	exponent := value >> 24
-	mantissa := value & 0xFFFFFF
-	expected := 256 - (math.Log2(float64(mantissa)) + 8*float64(exponent-3))
-	if !roughlyEqual(note.ZBits, expected) {
-		t.Fatalf("unexpected zbits: got %f want %f", note.ZBits, expected)
+	const golden = `{"min":1500000000,"max":2250000000,"min_human":"1.50 GH/s","max_human":"2.25 GH/s"}`
+	if string(encoded) != golden {
+		t.Fatalf("unexpected JSON:\n got %s\nwant %s", encoded, golden)
 	}
-	if note.Label() != "57Z12" {
-		t.Fatalf("unexpected label: %s", note.Label())
+	var decoded HashrateRange
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != r {
+		t.Fatalf("round trip mismatch: %+v", decoded)
 	}
 }
 
-func TestNBitsRoundTrip(t *testing.T) {
-	label := "57Z12"
-	note := mustParseLabel(label)
-	nbits, err := SharenoteToNBits(note)
+func TestHashrateRangeClosedUpper(t *testing.T) {
+	open, err := HashrateRangeForNote("33Z53", 5)
 	if err != nil {
-		t.Fatalf("SharenoteToNBits: %v", err)
+		t.Fatal(err)
 	}
-	rtNote, err := NBitsToSharenote(nbits)
+	closed, err := HashrateRangeForNote("33Z53", 5, WithRangeClosedUpper())
 	if err != nil {
-		t.Fatalf("NBitsToSharenote: %v", err)
-	}
-	if rtNote.Label() != label {
-		t.Fatalf("round trip mismatch: got %s want %s", rtNote.Label(), label)
+		t.Fatal(err)
 	}
-}
-
-func TestHumaniseHashratePrecision(t *testing.T) {
-	human := HumaniseHashrate(12.34e9, WithHumanHashratePrecision(5))
-	expected := fmt.Sprintf("%.5f %s", human.Value, human.Unit)
-	if human.Display != expected {
-		t.Fatalf("precision formatting mismatch: got %s want %s", human.Display, expected)
+	if closed.Min != open.Min || !(closed.Max < open.Max) {
+		t.Fatalf("unexpected closed range %+v vs open %+v", closed, open)
 	}
-}
-
-func TestHumaniseHashrateTinyInputs(t *testing.T) {
-	human := HumaniseHashrate(0.25, WithHumanHashratePrecision(2))
-	if human.Unit != HashrateUnitHps {
-		t.Fatalf("expected H/s unit for tiny hashrates, got %s", human.Unit)
+	for _, rate := range []float64{closed.Max, open.Min} {
+		note, err := NoteFromHashrate(HashrateValue{Value: rate}, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if note.Label() != "33Z53" {
+			t.Fatalf("hashrate %f mapped to %s, want 33Z53", rate, note.Label())
+		}
 	}
-	if human.Display != "0.25 H/s" {
-		t.Fatalf("unexpected display for tiny hashrate: %s", human.Display)
+	next, err := NoteFromHashrate(HashrateValue{Value: open.Max}, 5)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestReliabilityLevels(t *testing.T) {
-	levels := ReliabilityLevels()
-	if len(levels) == 0 {
-		t.Fatal("expected reliability levels")
+	if next.Label() != "33Z54" {
+		t.Fatalf("exclusive upper mapped to %s, want 33Z54", next.Label())
 	}
 }
 
-func TestSharenoteConvenienceMethods(t *testing.T) {
-	note := mustParseLabel("33Z53")
-
-	probNote, err := note.ProbabilityPerHash()
+func TestAchievableNotes(t *testing.T) {
+	hashrate := HashrateValue{Value: 1000}
+	notes, err := AchievableNotes(hashrate, 1)
 	if err != nil {
-		t.Fatalf("note ProbabilityPerHash: %v", err)
+		t.Fatal(err)
 	}
-	probFunc, err := ProbabilityPerHash(note)
+	maxNote, err := MaxNoteForHashrate(hashrate, 1)
 	if err != nil {
-		t.Fatalf("func ProbabilityPerHash: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(probNote, probFunc) {
-		t.Fatalf("prob mismatch: note=%f func=%f", probNote, probFunc)
+	if len(notes) != 997 {
+		t.Fatalf("expected 997 notes up to 9Z96, got %d", len(notes))
+	}
+	if notes[0].Label() != "0Z00" || notes[len(notes)-1].Label() != maxNote.Label() {
+		t.Fatalf("unexpected bounds: %s..%s, max %s", notes[0], notes[len(notes)-1], maxNote)
 	}
+	if _, err := AchievableNotes(HashrateValue{Value: 1e90}, 1); err == nil {
+		t.Fatal("expected error for range above the cap")
+	}
+}
 
-	expectedNote, err := note.ExpectedHashes()
+func TestExpectedNotesInWindow(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	mean, err := RequiredHashrateMean(note, 5)
 	if err != nil {
-		t.Fatalf("note ExpectedHashes: %v", err)
+		t.Fatal(err)
 	}
-	expectedFunc, err := ExpectedHashesForNote(note)
+	lambda, err := ExpectedNotesInWindow(note, mean.Float64(), 5)
 	if err != nil {
-		t.Fatalf("func ExpectedHashesForNote: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(expectedNote.Float64(), expectedFunc.Float64()) {
-		t.Fatalf("expected hashes mismatch: note=%f func=%f", expectedNote.Float64(), expectedFunc.Float64())
+	if !roughlyEqual(lambda, 1) {
+		t.Fatalf("lambda = %f, want 1", lambda)
 	}
-
-	const seconds = 5.0
-	meanNote, err := note.RequiredHashrateMean(seconds)
+	probability, err := SuccessProbability(note, mean.Float64(), 5)
 	if err != nil {
-		t.Fatalf("note RequiredHashrateMean: %v", err)
+		t.Fatal(err)
 	}
-	meanFunc, err := RequiredHashrateMean(note, seconds)
-	if err != nil {
-		t.Fatalf("func RequiredHashrateMean: %v", err)
+	if !roughlyEqual(probability, -math.Expm1(-lambda)) {
+		t.Fatalf("SuccessProbability = %f, want 1-exp(-%f)", probability, lambda)
 	}
-	if !roughlyEqual(meanNote.Float64(), meanFunc.Float64()) {
-		t.Fatalf("mean mismatch: note=%f func=%f", meanNote.Float64(), meanFunc.Float64())
+	if _, err := ExpectedNotesInWindow(note, 0, 5); err == nil {
+		t.Fatal("expected error for zero hashrate")
 	}
+	if _, err := ExpectedNotesInWindow(note, 1e9, -1); err == nil {
+		t.Fatal("expected error for negative seconds")
+	}
+}
 
-	const confidence = 0.95
-	quantNote, err := note.RequiredHashrateQuantile(seconds, confidence)
+func TestWindowForNote(t *testing.T) {
+	window, err := WindowForNote("33Z53", 2e9, 0.95)
 	if err != nil {
-		t.Fatalf("note RequiredHashrateQuantile: %v", err)
+		t.Fatal(err)
 	}
-	quantFunc, err := RequiredHashrateQuantile(note, seconds, confidence)
+	probability, err := SuccessProbability("33Z53", 2e9, window)
 	if err != nil {
-		t.Fatalf("func RequiredHashrateQuantile: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(quantNote.Float64(), quantFunc.Float64()) {
-		t.Fatalf("quantile mismatch: note=%f func=%f", quantNote.Float64(), quantFunc.Float64())
+	if !roughlyEqual(probability, 0.95) {
+		t.Fatalf("SuccessProbability = %f, want 0.95", probability)
 	}
-
-	generalNote, err := note.RequiredHashrate(seconds, WithMultiplier(2))
+	required, err := RequiredHashrateQuantile("33Z53", window, 0.95)
 	if err != nil {
-		t.Fatalf("note RequiredHashrate: %v", err)
+		t.Fatal(err)
 	}
-	generalFunc, err := RequiredHashrate(note, seconds, WithMultiplier(2))
-	if err != nil {
-		t.Fatalf("func RequiredHashrate: %v", err)
+	if !roughlyEqual(required.Value, 2e9) {
+		t.Fatalf("window does not invert RequiredHashrateQuantile: %f", required.Value)
 	}
-	if !roughlyEqual(generalNote.Float64(), generalFunc.Float64()) {
-		t.Fatalf("general mismatch: note=%f func=%f", generalNote.Float64(), generalFunc.Float64())
+	for _, confidence := range []float64{0, 1} {
+		if _, err := WindowForNote("33Z53", 2e9, confidence); err == nil {
+			t.Fatalf("expected error for confidence %v", confidence)
+		}
+	}
+	if _, err := WindowForNote("33Z53", 0, 0.5); err == nil {
+		t.Fatal("expected error for zero hashrate")
 	}
+}
 
-	measurement, err := note.RequiredHashrateMeasurement(seconds)
+func TestFleetCoverage(t *testing.T) {
+	// 33Z53 over 5s at 95% needs ~7.43 GH/s.
+	fleet := []float64{1e9, 5e9, 7.5e9, 10e9, 20e9}
+	fraction, err := FleetCoverage("33Z53", fleet, 5, 0.95)
 	if err != nil {
-		t.Fatalf("note RequiredHashrateMeasurement: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(measurement.Float64(), meanFunc.Float64()) {
-		t.Fatalf("measurement mismatch: %f vs %f", measurement.Float64(), meanFunc.Float64())
+	if !roughlyEqual(fraction, 0.6) {
+		t.Fatalf("FleetCoverage = %f, want 0.6", fraction)
 	}
-	if measurement.String() != measurement.Human().String() {
-		t.Fatalf("measurement String mismatch: %s vs %s", measurement.String(), measurement.Human())
+	if _, err := FleetCoverage("33Z53", nil, 5, 0.95); err == nil {
+		t.Fatal("expected error for empty fleet")
 	}
-	customHuman := measurement.Human(WithHumanHashratePrecision(4))
-	expectedDisplay := fmt.Sprintf("%.4f %s", customHuman.Value, customHuman.Unit)
-	if customHuman.Display != expectedDisplay {
-		t.Fatalf("custom precision mismatch: got %s want %s", customHuman.Display, expectedDisplay)
+	if _, err := FleetCoverage("33Z53", fleet, 5, 1); err == nil {
+		t.Fatal("expected error for confidence 1")
 	}
+	if _, err := FleetCoverage("33Z53", []float64{-1}, 5, 0.95); err == nil {
+		t.Fatal("expected error for negative hashrate")
+	}
+}
 
-	meanMeasurement, err := note.RequiredHashrateMeanMeasurement(seconds)
+func TestMarginalDifficultyRatio(t *testing.T) {
+	ratio, err := MarginalDifficultyRatio("33Z53")
 	if err != nil {
-		t.Fatalf("note RequiredHashrateMeanMeasurement: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(meanMeasurement.Float64(), meanFunc.Float64()) {
-		t.Fatalf("mean measurement mismatch: %f vs %f", meanMeasurement.Float64(), meanFunc.Float64())
+	if math.Abs(ratio-1.00696) > 1e-5 {
+		t.Fatalf("grid ratio = %f, want ~1.00696", ratio)
 	}
-
-	quantMeasurement, err := note.RequiredHashrateQuantileMeasurement(seconds, confidence)
+	halfway, err := MarginalDifficultyRatio(33.535)
 	if err != nil {
-		t.Fatalf("note RequiredHashrateQuantileMeasurement: %v", err)
+		t.Fatal(err)
 	}
-	if !roughlyEqual(quantMeasurement.Float64(), quantFunc.Float64()) {
-		t.Fatalf("quant measurement mismatch: %f vs %f", quantMeasurement.Float64(), quantFunc.Float64())
+	if !roughlyEqual(halfway, math.Exp2(0.005)) {
+		t.Fatalf("sub-cent ratio = %f, want %f", halfway, math.Exp2(0.005))
 	}
+}
 
-	scaledNote, err := note.Scale(1.5)
+func TestProbabilityForAttempts(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	expected, err := note.ExpectedHashes()
 	if err != nil {
-		t.Fatalf("note Scale: %v", err)
+		t.Fatal(err)
 	}
-	scaledFunc, err := ScaleNote(note, 1.5)
+	probability, err := ProbabilityForAttempts(note, expected.Value)
 	if err != nil {
-		t.Fatalf("func ScaleNote: %v", err)
+		t.Fatal(err)
 	}
-	if scaledNote.Label() != scaledFunc.Label() {
-		t.Fatalf("scale mismatch: note=%s func=%s", scaledNote.Label(), scaledFunc.Label())
+	if math.Abs(probability-(1-math.Exp(-1))) > 1e-6 {
+		t.Fatalf("ProbabilityForAttempts = %f, want ~0.632", probability)
+	}
+	if p, err := ProbabilityForAttempts(note, 0); err != nil || p != 0 {
+		t.Fatalf("zero attempts = %f, %v", p, err)
 	}
+	if _, err := ProbabilityForAttempts(note, -1); err == nil {
+		t.Fatal("expected error for negative attempts")
+	}
+}
 
-	combinedNote, err := note.CombineSerial("33Z53")
+func TestCompareRigs(t *testing.T) {
+	slow, fast, err := CompareRigs("33Z53", 1e9, 4e9, WithReliability(ReliabilityOften95))
 	if err != nil {
-		t.Fatalf("note CombineSerial: %v", err)
+		t.Fatal(err)
 	}
-	combinedFunc, err := CombineNotesSerial(note, "33Z53")
+	if !(fast < slow) || !roughlyEqual(slow/fast, 4) {
+		t.Fatalf("unexpected windows: slow=%f fast=%f", slow, fast)
+	}
+	required, err := RequiredHashrate("33Z53", fast, WithReliability(ReliabilityOften95))
 	if err != nil {
-		t.Fatalf("func CombineNotesSerial: %v", err)
+		t.Fatal(err)
 	}
-	if combinedNote.Label() != combinedFunc.Label() {
-		t.Fatalf("combine mismatch: note=%s func=%s", combinedNote.Label(), combinedFunc.Label())
+	if !roughlyEqual(required.Value, 4e9) {
+		t.Fatalf("fast window does not invert RequiredHashrate: %f", required.Value)
+	}
+	if _, _, err := CompareRigs("33Z53", 0, 1e9); err == nil {
+		t.Fatal("expected error for zero hashrate")
 	}
+}
 
-	diffNote, err := note.Difference("32Z00")
+func TestFilterAndMapNotes(t *testing.T) {
+	notes := []Sharenote{mustParseLabel("31Z10"), mustParseLabel("33Z53"), mustParseLabel("34Z00")}
+	hard := FilterNotes(notes, func(n Sharenote) bool { return n.Z >= 33 })
+	labels, err := MapNotes(hard, func(n Sharenote) (string, error) { return n.Label(), nil })
 	if err != nil {
-		t.Fatalf("note Difference: %v", err)
+		t.Fatal(err)
 	}
-	diffFunc, err := NoteDifference(note, "32Z00")
-	if err != nil {
-		t.Fatalf("func NoteDifference: %v", err)
+	if strings.Join(labels, ",") != "33Z53,34Z00" {
+		t.Fatalf("unexpected labels: %v", labels)
 	}
-	if diffNote.Label() != diffFunc.Label() {
-		t.Fatalf("difference mismatch: note=%s func=%s", diffNote.Label(), diffFunc.Label())
+	if _, err := MapNotes(notes, func(n Sharenote) (Sharenote, error) { return n.Prev() }); err != nil {
+		t.Fatal(err)
 	}
+	if _, err := MapNotes([]Sharenote{{}}, func(n Sharenote) (Sharenote, error) { return n.Prev() }); err == nil {
+		t.Fatal("expected error to propagate from fn")
+	}
+}
 
-	targetNote, err := note.Target()
+func TestScaleNoteToHashrate(t *testing.T) {
+	scaled, factor, err := ScaleNoteToHashrate("33Z53", 20e9, 5, WithReliability(ReliabilityOften95))
 	if err != nil {
-		t.Fatalf("note Target: %v", err)
+		t.Fatal(err)
 	}
-	targetFunc, err := TargetFor(note)
+	required, err := RequiredHashrate(scaled, 5, WithReliability(ReliabilityOften95))
 	if err != nil {
-		t.Fatalf("func TargetFor: %v", err)
+		t.Fatal(err)
 	}
-	if targetNote.Cmp(targetFunc) != 0 {
-		t.Fatalf("target mismatch: note=%s func=%s", targetNote, targetFunc)
+	if !roughlyEqual(required.Value, 20e9) {
+		t.Fatalf("scaled note requires %f, want 20e9", required.Value)
 	}
-
-	nbitsNote, err := note.NBits()
-	if err != nil {
-		t.Fatalf("note NBits: %v", err)
+	if !roughlyEqual(factor, 20e9/7.431367665e9) {
+		t.Fatalf("unexpected factor: %f", factor)
 	}
-	nbitsFunc, err := SharenoteToNBits(note)
-	if err != nil {
-		t.Fatalf("func SharenoteToNBits: %v", err)
+	if _, _, err := ScaleNoteToHashrate("33Z53", 0, 5); err == nil {
+		t.Fatal("expected error for zero target")
 	}
-	if nbitsNote != nbitsFunc {
-		t.Fatalf("nbits mismatch: note=%s func=%s", nbitsNote, nbitsFunc)
+	if _, _, err := ScaleNoteToHashrate("33Z53", 1e9, 0); err == nil {
+		t.Fatal("expected error for zero window")
 	}
 }
 
-func TestEnsureNote(t *testing.T) {
-	note := mustParseLabel("33Z53")
-	resolved, err := EnsureNote(note)
-	if err != nil {
-		t.Fatal(err)
+func TestHashrateQuantiles(t *testing.T) {
+	var exact HashrateQuantiles
+	if !math.IsNaN(exact.Percentile(0.5)) {
+		t.Fatal("expected NaN before any observations")
 	}
-	if resolved.Label() != "33Z53" {
-		t.Fatalf("ensure note mismatch: %s", resolved.Label())
+	for i := 1; i <= 101; i++ {
+		exact.Observe(float64(i) * 1e9)
 	}
-	resolved, err = EnsureNote(33.53)
-	if err != nil {
-		t.Fatalf("EnsureNote zbits: %v", err)
+	if got := exact.Percentile(0.5); !roughlyEqual(got, 51e9) {
+		t.Fatalf("p50 = %f, want 51e9", got)
 	}
-	if resolved.Label() != "33Z53" {
-		t.Fatalf("unexpected label from zbits: %s", resolved.Label())
+	if got := exact.Percentile(0.95); !roughlyEqual(got, 96e9) {
+		t.Fatalf("p95 = %f, want 96e9", got)
 	}
-	resolved, err = EnsureNote(33)
-	if err != nil {
-		t.Fatalf("EnsureNote integer: %v", err)
+
+	sampled := HashrateQuantiles{Capacity: 256}
+	for i := 0; i < 20_000; i++ {
+		sampled.Observe(float64(i%1000) * 1e6)
 	}
-	if resolved.Label() != "33Z00" {
-		t.Fatalf("unexpected label from integer zbits: %s", resolved.Label())
+	if sampled.Count() != 20_000 {
+		t.Fatalf("unexpected count: %d", sampled.Count())
 	}
-	resolved, err = EnsureNote(uint32(1))
-	if err != nil {
-		t.Fatalf("EnsureNote uint32: %v", err)
+	if got := sampled.Percentile(0.5); math.Abs(got-500e6) > 100e6 {
+		t.Fatalf("reservoir p50 = %f, want near 500e6", got)
 	}
-	if resolved.Label() != "1Z00" {
-		t.Fatalf("unexpected label from uint32 zbits: %s", resolved.Label())
+	note, err := NoteFromHashrate(HashrateValue{Value: exact.Percentile(0.95)}, 5)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if _, err := EnsureNote(-1.0); err == nil {
-		t.Fatal("expected error for negative zbits input")
+	if note.Z < 38 {
+		t.Fatalf("unexpected note from p95: %s", note)
 	}
-	if _, err := EnsureNote(int(-1)); err == nil {
-		t.Fatal("expected error for negative integer zbits input")
+}
+
+func TestHashrateRangeString(t *testing.T) {
+	rng := HashrateRange{Min: 1e9, Max: 5e9}
+	if got := rng.String(); got != "[1.00 GH/s, 5.00 GH/s)" {
+		t.Fatalf("unexpected range string: %s", got)
 	}
-	if _, err := EnsureNote(true); err == nil {
-		t.Fatal("expected error for unsupported type")
+	if got := fmt.Sprint(HashrateRange{Min: 2e12, Max: 2e12}); got != "[2.00 TH/s]" {
+		t.Fatalf("unexpected degenerate range string: %s", got)
 	}
 }
 
-func TestNoteFromCentZBits(t *testing.T) {
-	note, err := NoteFromCentZBits(3353)
+func TestHashrateStepToNextNote(t *testing.T) {
+	step, err := HashrateStepToNextNote("33Z53", 5)
 	if err != nil {
-		t.Fatalf("NoteFromCentZBits: %v", err)
+		t.Fatal(err)
 	}
-	if note.Label() != "33Z53" {
-		t.Fatalf("unexpected label: %s", note.Label())
+	if step <= 0 {
+		t.Fatalf("expected positive step, got %f", step)
 	}
-	if note.Z != 33 || note.Cents != 53 {
-		t.Fatalf("unexpected components: %+v", note)
+	mean, err := RequiredHashrateMean("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
 	}
-	note = MustNoteFromCentZBits(centZUnitsPerZ + 1)
-	if note.Label() != "1Z01" {
-		t.Fatalf("unexpected label from MustNoteFromCentZBits: %s", note.Label())
+	expected := mean.Float64() * (math.Exp2(CentZBitStep) - 1)
+	if !roughlyEqual(step, expected) {
+		t.Fatalf("step mismatch: got %f want %f", step, expected)
 	}
-	if _, err := NoteFromCentZBits(-1); err == nil {
-		t.Fatal("expected error for negative cent-z input")
+	if _, err := HashrateStepToNextNote("33Z53", 0); err == nil {
+		t.Fatal("expected error for zero window")
 	}
 }
 
-func TestTargetDeterministic(t *testing.T) {
-	note := mustParseLabel("57Z12")
-	target, err := TargetFor(note)
+func TestHashrateRangeReliabilityScaling(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	base, err := HashrateRangeForNote(note, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if target.Sign() <= 0 {
-		t.Fatal("target should be positive")
-	}
-	// Validate monotonicity
-	next, err := TargetFor("57Z13")
+	often, err := HashrateRangeForNote(note, 5, WithReliability(ReliabilityOften95))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if next.Cmp(target) >= 0 {
-		t.Fatal("harder note should yield smaller target")
+	if often.Min <= base.Min {
+		t.Fatalf("expected reliability range min > base min, got %f <= %f", often.Min, base.Min)
 	}
-
+	if often.Max <= base.Max {
+		t.Fatalf("expected reliability range max > base max, got %f <= %f", often.Max, base.Max)
+	}
+}
+
+func TestParseHashrate(t *testing.T) {
+	value, err := ParseHashrate("5 GH/s")
+	if err != nil {
+		t.Fatalf("ParseHashrate: %v", err)
+	}
+	if !roughlyEqual(value, 5e9) {
+		t.Fatalf("unexpected parsed value: %f", value)
+	}
+	value, err = ParseHashrate("12.5 MH/s")
+	if err != nil {
+		t.Fatalf("ParseHashrate words: %v", err)
+	}
+	if !roughlyEqual(value, 12.5e6) {
+		t.Fatalf("unexpected parsed value: %f", value)
+	}
+	if _, err := ParseHashrate("12 foo/s"); err == nil {
+		t.Fatal("expected error for invalid unit")
+	}
+}
+
+func TestAddHashrateValues(t *testing.T) {
+	sum, err := AddHashrateValues(
+		HashrateValue{Value: 500, Unit: HashrateUnitMHps},
+		HashrateValue{Value: 2, Unit: HashrateUnitGHps},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Unit != HashrateUnitGHps || !roughlyEqual(sum.Value, 2.5) {
+		t.Fatalf("unexpected sum: %+v", sum)
+	}
+	if _, err := AddHashrateValues(HashrateValue{Value: 1, Unit: "bogus"}); err == nil {
+		t.Fatal("expected error for invalid unit")
+	}
+	if _, err := AddHashrateValues(); err == nil {
+		t.Fatal("expected error for no values")
+	}
+}
+
+func TestParseHashratePerSecondSpellings(t *testing.T) {
+	for _, input := range []string{"5 Gh/sec", "5 GH/second", "5 Gh", "5GHPS", "5 GH/s"} {
+		got, err := ParseHashrate(input)
+		if err != nil {
+			t.Fatalf("ParseHashrate(%q): %v", input, err)
+		}
+		if !roughlyEqual(got, 5e9) {
+			t.Fatalf("ParseHashrate(%q) = %f, want 5e9", input, got)
+		}
+	}
+}
+
+func TestRoundHashrateUp(t *testing.T) {
+	cases := []struct {
+		hashrate float64
+		step     float64
+		unit     HashrateUnit
+		want     float64
+	}{
+		{7.43e9, 0.5, HashrateUnitGHps, 7.5e9},
+		{7.5e9, 0.5, HashrateUnitGHps, 7.5e9},
+		{7.43e9, 100, HashrateUnitMHps, 7.5e9},
+		{1, 1, HashrateUnitTHps, 1e12},
+		{0, 1, HashrateUnitGHps, 0},
+	}
+	for _, tc := range cases {
+		got, err := RoundHashrateUp(tc.hashrate, tc.step, tc.unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !roughlyEqual(got, tc.want) {
+			t.Fatalf("RoundHashrateUp(%g, %g %s) = %g, want %g", tc.hashrate, tc.step, tc.unit, got, tc.want)
+		}
+	}
+	if _, err := RoundHashrateUp(7.43e9, 0, HashrateUnitGHps); err == nil {
+		t.Fatal("expected error for zero step")
+	}
+}
+
+func TestHashrateValueText(t *testing.T) {
+	type config struct {
+		Rig HashrateValue `json:"rig"`
+	}
+	original := config{Rig: HashrateValue{Value: 7.4314, Unit: HashrateUnitGHps}}
+	encoded, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"rig":"7.4314 GH/s"}` {
+		t.Fatalf("unexpected JSON: %s", encoded)
+	}
+	var decoded config
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := NormalizeHashrateValue(original.Rig)
+	got, err := NormalizeHashrateValue(decoded.Rig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(got, want) || decoded.Rig.Unit != HashrateUnitGHps {
+		t.Fatalf("round trip mismatch: %+v", decoded.Rig)
+	}
+	if parsed, err := ParseHashrateValue("5 gh/sec"); err != nil || parsed != (HashrateValue{Value: 5, Unit: HashrateUnitGHps}) {
+		t.Fatalf("ParseHashrateValue = %+v, %v", parsed, err)
+	}
+	if err := decoded.Rig.UnmarshalText([]byte("5 parsecs")); err == nil {
+		t.Fatal("expected error for unknown unit")
+	}
+}
+
+func TestParseHashrateLenient(t *testing.T) {
+	value, recognized, err := ParseHashrateLenient("5 xyz/s")
+	if err != nil || value != 5 || recognized {
+		t.Fatalf("ParseHashrateLenient(5 xyz/s) = %f, %v, %v", value, recognized, err)
+	}
+	value, recognized, err = ParseHashrateLenient("5 GH/s")
+	if err != nil || !roughlyEqual(value, 5e9) || !recognized {
+		t.Fatalf("ParseHashrateLenient(5 GH/s) = %f, %v, %v", value, recognized, err)
+	}
+	if _, _, err := ParseHashrateLenient("abc GH/s"); err == nil {
+		t.Fatal("expected error for bad magnitude")
+	}
+}
+
+func TestParseHashrateStrict(t *testing.T) {
+	if _, err := ParseHashrateStrict("5"); err == nil {
+		t.Fatal("expected strict parse to reject a bare magnitude")
+	}
+	if v, err := ParseHashrate("5"); err != nil || v != 5 {
+		t.Fatalf("lenient ParseHashrate(5) = %f, %v", v, err)
+	}
+	for input, want := range map[string]float64{"5 H/s": 5, "2.5 GH/s": 2.5e9} {
+		got, err := ParseHashrateStrict(input)
+		if err != nil {
+			t.Fatalf("ParseHashrateStrict(%q): %v", input, err)
+		}
+		if !roughlyEqual(got, want) {
+			t.Fatalf("ParseHashrateStrict(%q) = %f, want %f", input, got, want)
+		}
+	}
+}
+
+func TestParseSolutionRate(t *testing.T) {
+	cases := []struct {
+		input string
+		want  float64
+	}{
+		{"5 kSol/s", 5000},
+		{"2 MSol/s", 2e6},
+		{"7 solutions/s", 7},
+	}
+	for _, tc := range cases {
+		got, err := ParseSolutionRate(tc.input)
+		if err != nil {
+			t.Fatalf("ParseSolutionRate(%q): %v", tc.input, err)
+		}
+		if !roughlyEqual(got, tc.want) {
+			t.Fatalf("ParseSolutionRate(%q) = %f, want %f", tc.input, got, tc.want)
+		}
+	}
+	if _, err := ParseSolutionRate("5 kfoo/s"); err == nil {
+		t.Fatal("expected error for invalid unit")
+	}
+}
+
+func TestParseDurationHuman(t *testing.T) {
+	cases := []struct {
+		input string
+		want  float64
+	}{
+		{"500 ms", 0.5},
+		{"2 days", 172_800},
+		{"3.2 days", 276_480},
+		{"90s", 90},
+		{"1 year", 31_557_600},
+	}
+	for _, tc := range cases {
+		got, err := ParseDurationHuman(tc.input)
+		if err != nil {
+			t.Fatalf("ParseDurationHuman(%q): %v", tc.input, err)
+		}
+		if !roughlyEqual(got, tc.want) {
+			t.Fatalf("ParseDurationHuman(%q) = %f, want %f", tc.input, got, tc.want)
+		}
+	}
+	if _, err := ParseDurationHuman("5 fortnights"); err == nil {
+		t.Fatal("expected error for invalid unit")
+	}
+}
+
+func TestNoteFromBitsString(t *testing.T) {
+	note, err := NoteFromBitsString(" 33.53 bits ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Label() != "33Z53" || !roughlyEqual(note.ZBits, 33.53) {
+		t.Fatalf("unexpected note %s (%f)", note.Label(), note.ZBits)
+	}
+	note, err = NoteFromBitsString("57b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Label() != "57Z00" {
+		t.Fatalf("expected 57Z00, got %s", note.Label())
+	}
+	for _, input := range []string{"33.53 bytes", "1e20b"} {
+		if _, err := NoteFromBitsString(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	got, err := ParseDifficulty("26.64 T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(got, 26.64e12) {
+		t.Fatalf("ParseDifficulty = %f", got)
+	}
+	if formatted := FormatDifficulty(got); formatted != "26.64 T" {
+		t.Fatalf("FormatDifficulty = %q", formatted)
+	}
+	if v, err := ParseDifficulty("500k"); err != nil || !roughlyEqual(v, 500e3) {
+		t.Fatalf("ParseDifficulty(500k) = %f, %v", v, err)
+	}
+	if formatted := FormatDifficulty(512); formatted != "512.00" {
+		t.Fatalf("FormatDifficulty(512) = %q", formatted)
+	}
+	if _, err := ParseDifficulty("12 X"); err == nil {
+		t.Fatal("expected error for unknown suffix")
+	}
+	note, err := NoteFromDifficulty(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(note.ZBits, math.Log2(26.64e12)) {
+		t.Fatalf("NoteFromDifficulty zbits = %f", note.ZBits)
+	}
+}
+
+func TestTargetFor(t *testing.T) {
+	target, err := TargetFor("33Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.BitLen() < 222 || target.BitLen() > 224 {
+		t.Fatalf("unexpected bit length %d", target.BitLen())
+	}
+}
+
+func TestTargetForInto(t *testing.T) {
+	dst := new(big.Int)
+	for _, label := range []string{"33Z53", "57Z12", "0Z00"} {
+		want, err := TargetFor(label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := TargetForInto(label, dst); err != nil {
+			t.Fatal(err)
+		}
+		if dst.Cmp(want) != 0 {
+			t.Fatalf("%s: TargetForInto = %s, want %s", label, dst, want)
+		}
+	}
+	if err := TargetForInto(MustNoteFromZBits(257), dst); err == nil {
+		t.Fatal("expected underflow error")
+	}
+	if err := TargetForInto("33Z53", nil); err == nil {
+		t.Fatal("expected error for nil dst")
+	}
+}
+
+func BenchmarkTargetFor(b *testing.B) {
+	note := mustParseLabel("33Z53")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := TargetFor(note); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTargetForInto(b *testing.B) {
+	note := mustParseLabel("33Z53")
+	dst := new(big.Int)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := TargetForInto(note, dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestNotesShareTarget(t *testing.T) {
+	a := MustNoteFromZBits(1.25)
+	b := MustNoteFromZBits(math.Nextafter(1.25, 2))
+	if a.ZBits == b.ZBits {
+		t.Fatal("expected distinct zbits")
+	}
+	shared, err := NotesShareTarget(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shared {
+		t.Fatal("expected adjacent zbits to collapse to the same target")
+	}
+	shared, err = NotesShareTarget("33Z53", "33Z54")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared {
+		t.Fatal("expected 33Z53 and 33Z54 to have different targets")
+	}
+}
+
+func TestCompareNotes(t *testing.T) {
+	cmp, err := CompareNotes("32Z00", "33Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp >= 0 {
+		t.Fatal("expected 32Z00 < 33Z00")
+	}
+	cmp, err = CompareNotes("33Z54", "33Z53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp <= 0 {
+		t.Fatal("expected 33Z54 > 33Z53")
+	}
+}
+
+func TestCompareNotesByTarget(t *testing.T) {
+	easier := MustNoteFromZBits(33.531)
+	harder := MustNoteFromZBits(33.538)
+	if easier.Label() != harder.Label() {
+		t.Fatalf("expected equal labels, got %s and %s", easier, harder)
+	}
+	if byLabel, err := CompareNotes(easier, harder); err != nil || byLabel != 0 {
+		t.Fatalf("CompareNotes = %d, %v; want 0", byLabel, err)
+	}
+	byTarget, err := CompareNotesByTarget(easier, harder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byTarget != -1 {
+		t.Fatalf("CompareNotesByTarget(easier, harder) = %d, want -1", byTarget)
+	}
+	if byTarget, err = CompareNotesByTarget(harder, easier); err != nil || byTarget != 1 {
+		t.Fatalf("CompareNotesByTarget(harder, easier) = %d, %v; want 1", byTarget, err)
+	}
+	if byTarget, err = CompareNotesByTarget("33Z53", "33Z53"); err != nil || byTarget != 0 {
+		t.Fatalf("CompareNotesByTarget(equal) = %d, %v; want 0", byTarget, err)
+	}
+}
+
+func TestOddsString(t *testing.T) {
+	odds, err := mustParseLabel("33Z53").OddsString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if odds != "1 in 12.4 billion" {
+		t.Fatalf("unexpected odds: %q", odds)
+	}
+	if odds, err := mustParseLabel("8Z00").OddsString(); err != nil || odds != "1 in 256" {
+		t.Fatalf("unexpected small odds: %q, %v", odds, err)
+	}
+	if _, err := MustNoteFromZBits(2000).OddsString(); err == nil {
+		t.Fatal("expected overflow error")
+	}
+}
+
+func TestNotePredicates(t *testing.T) {
+	ref := mustParseLabel("33Z53")
+
+	harder, err := ref.IsHarderThan("33Z52")
+	if err != nil || !harder {
+		t.Fatalf("expected 33Z53 harder than 33Z52: %v, %v", harder, err)
+	}
+	harder, err = ref.IsHarderThan("33Z53")
+	if err != nil || harder {
+		t.Fatalf("expected 33Z53 not harder than itself: %v, %v", harder, err)
+	}
+	easier, err := ref.IsEasierThan("34Z00")
+	if err != nil || !easier {
+		t.Fatalf("expected 33Z53 easier than 34Z00: %v, %v", easier, err)
+	}
+	easier, err = ref.IsEasierThan("20Z10")
+	if err != nil || easier {
+		t.Fatalf("expected 33Z53 not easier than 20Z10: %v, %v", easier, err)
+	}
+
+	equal, err := ref.EqualsNote(33.535, 0.01)
+	if err != nil || !equal {
+		t.Fatalf("expected 33.535 within tolerance: %v, %v", equal, err)
+	}
+	equal, err = ref.EqualsNote(33.535, 0.001)
+	if err != nil || equal {
+		t.Fatalf("expected 33.535 outside tight tolerance: %v, %v", equal, err)
+	}
+	if _, err := ref.EqualsNote("33Z53", -1); err == nil {
+		t.Fatal("expected error for negative tolerance")
+	}
+	if _, err := ref.IsHarderThan("bogus"); err == nil {
+		t.Fatal("expected error for invalid note")
+	}
+}
+
+func TestNBitsConversion(t *testing.T) {
+	note, err := NBitsToSharenote("19752b59")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ := strconv.ParseUint("19752b59", 16, 32)
+	exponent := value >> 24
+	mantissa := value & 0xFFFFFF
+	expected := 256 - (math.Log2(float64(mantissa)) + 8*float64(exponent-3))
+	if !roughlyEqual(note.ZBits, expected) {
+		t.Fatalf("unexpected zbits: got %f want %f", note.ZBits, expected)
+	}
+	if note.Label() != "57Z12" {
+		t.Fatalf("unexpected label: %s", note.Label())
+	}
+}
+
+func TestNBitsRoundTrip(t *testing.T) {
+	label := "57Z12"
+	note := mustParseLabel(label)
+	nbits, err := SharenoteToNBits(note)
+	if err != nil {
+		t.Fatalf("SharenoteToNBits: %v", err)
+	}
+	rtNote, err := NBitsToSharenote(nbits)
+	if err != nil {
+		t.Fatalf("NBitsToSharenote: %v", err)
+	}
+	if rtNote.Label() != label {
+		t.Fatalf("round trip mismatch: got %s want %s", rtNote.Label(), label)
+	}
+}
+
+func TestNBitsPoolDifficulty(t *testing.T) {
+	diff, err := NBitsToPoolDifficulty("1d00ffff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != 1 {
+		t.Fatalf("difficulty-1 nBits = %f, want 1", diff)
+	}
+	nbits, err := PoolDifficultyToNBits(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nbits != "1d00ffff" {
+		t.Fatalf("PoolDifficultyToNBits(1) = %s, want 1d00ffff", nbits)
+	}
+	for _, want := range []float64{2, 1024, 65536, 26.64e12} {
+		nbits, err := PoolDifficultyToNBits(want)
+		if err != nil {
+			t.Fatalf("PoolDifficultyToNBits(%g): %v", want, err)
+		}
+		got, err := NBitsToPoolDifficulty(nbits)
+		if err != nil {
+			t.Fatalf("NBitsToPoolDifficulty(%s): %v", nbits, err)
+		}
+		// nBits keeps at least 15 significant mantissa bits.
+		if math.Abs(got-want)/want > 1e-4 {
+			t.Fatalf("round trip %g -> %s -> %g", want, nbits, got)
+		}
+	}
+	if _, err := PoolDifficultyToNBits(0); err == nil {
+		t.Fatal("expected error for zero difficulty")
+	}
+	if _, err := NBitsToPoolDifficulty("1d80ffff"); err == nil {
+		t.Fatal("expected error for negative target")
+	}
+}
+
+func TestSharenotePoolDifficulty(t *testing.T) {
+	note := mustParseLabel("32Z00")
+	difficulty, err := note.PoolDifficulty()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(difficulty-1) > 1e-4 {
+		t.Fatalf("PoolDifficulty = %f, want ~1.0", difficulty)
+	}
+	viaFunc, err := PoolDifficultyForNote(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if viaFunc != difficulty {
+		t.Fatalf("PoolDifficultyForNote = %f, method = %f", viaFunc, difficulty)
+	}
+}
+
+func TestHashrateUnitFor(t *testing.T) {
+	unit, exponent, err := HashrateUnitFor(3.2e9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit != HashrateUnitGHps || exponent != 3 {
+		t.Fatalf("HashrateUnitFor(3.2e9) = %s, %d", unit, exponent)
+	}
+	if unit, exponent, err := HashrateUnitFor(0); err != nil || unit != HashrateUnitHps || exponent != 0 {
+		t.Fatalf("HashrateUnitFor(0) = %s, %d, %v", unit, exponent, err)
+	}
+	if _, _, err := HashrateUnitFor(-1); err == nil {
+		t.Fatal("expected error for negative hashrate")
+	}
+}
+
+func TestHumaniseHashratePrecision(t *testing.T) {
+	human := HumaniseHashrate(12.34e9, WithHumanHashratePrecision(5))
+	expected := fmt.Sprintf("%.5f %s", human.Value, human.Unit)
+	if human.Display != expected {
+		t.Fatalf("precision formatting mismatch: got %s want %s", human.Display, expected)
+	}
+}
+
+func TestHumaniseHashrateNoSpace(t *testing.T) {
+	if got := HumaniseHashrate(3.2e9).Display; got != "3.20 GH/s" {
+		t.Fatalf("default display = %q", got)
+	}
+	if got := HumaniseHashrate(3.2e9, WithHumanHashrateNoSpace()).Display; got != "3.20GH/s" {
+		t.Fatalf("no-space display = %q", got)
+	}
+	if got := HumaniseHashrate(3.2e9, WithHumanHashrateNoSpace(), WithHumanHashratePrecision(3)).Display; got != "3.200GH/s" {
+		t.Fatalf("no-space with precision = %q", got)
+	}
+	if got := HumaniseHashrate(0, WithHumanHashrateNoSpace()).Display; got != "0H/s" {
+		t.Fatalf("no-space zero = %q", got)
+	}
+}
+
+func TestHumaniseHashrateTinyInputs(t *testing.T) {
+	human := HumaniseHashrate(0.25, WithHumanHashratePrecision(2))
+	if human.Unit != HashrateUnitHps {
+		t.Fatalf("expected H/s unit for tiny hashrates, got %s", human.Unit)
+	}
+	if human.Display != "0.25 H/s" {
+		t.Fatalf("unexpected display for tiny hashrate: %s", human.Display)
+	}
+}
+
+func TestReliabilityLevels(t *testing.T) {
+	levels := ReliabilityLevels()
+	if len(levels) == 0 {
+		t.Fatal("expected reliability levels")
+	}
+}
+
+func TestHashrateUnits(t *testing.T) {
+	units := HashrateUnits()
+	if len(units) != 8 {
+		t.Fatalf("expected 8 units, got %d", len(units))
+	}
+	if units[0].Unit != HashrateUnitHps || units[0].Exponent != 0 {
+		t.Fatalf("unexpected first unit: %+v", units[0])
+	}
+	if last := units[len(units)-1]; last.Unit != HashrateUnitZHps || last.Exponent != 7 {
+		t.Fatalf("unexpected last unit: %+v", last)
+	}
+	units[0].Unit = "bogus"
+	if HashrateUnits()[0].Unit != HashrateUnitHps {
+		t.Fatal("expected HashrateUnits to return a copy")
+	}
+}
+
+func TestRequiredHashrateString(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	got, err := RequiredHashrateString(note, 5, WithReliability(ReliabilityOften95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	measurement, err := note.RequiredHashrate(5, WithReliability(ReliabilityOften95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := measurement.Human().String(); got != want {
+		t.Fatalf("RequiredHashrateString = %q, want %q", got, want)
+	}
+	if _, err := RequiredHashrateString(note, 0); err == nil {
+		t.Fatal("expected error for zero seconds")
+	}
+}
+
+func TestSharenoteConvenienceMethods(t *testing.T) {
+	note := mustParseLabel("33Z53")
+
+	probNote, err := note.ProbabilityPerHash()
+	if err != nil {
+		t.Fatalf("note ProbabilityPerHash: %v", err)
+	}
+	probFunc, err := ProbabilityPerHash(note)
+	if err != nil {
+		t.Fatalf("func ProbabilityPerHash: %v", err)
+	}
+	if !roughlyEqual(probNote, probFunc) {
+		t.Fatalf("prob mismatch: note=%f func=%f", probNote, probFunc)
+	}
+
+	expectedNote, err := note.ExpectedHashes()
+	if err != nil {
+		t.Fatalf("note ExpectedHashes: %v", err)
+	}
+	expectedFunc, err := ExpectedHashesForNote(note)
+	if err != nil {
+		t.Fatalf("func ExpectedHashesForNote: %v", err)
+	}
+	if !roughlyEqual(expectedNote.Float64(), expectedFunc.Float64()) {
+		t.Fatalf("expected hashes mismatch: note=%f func=%f", expectedNote.Float64(), expectedFunc.Float64())
+	}
+
+	const seconds = 5.0
+	meanNote, err := note.RequiredHashrateMean(seconds)
+	if err != nil {
+		t.Fatalf("note RequiredHashrateMean: %v", err)
+	}
+	meanFunc, err := RequiredHashrateMean(note, seconds)
+	if err != nil {
+		t.Fatalf("func RequiredHashrateMean: %v", err)
+	}
+	if !roughlyEqual(meanNote.Float64(), meanFunc.Float64()) {
+		t.Fatalf("mean mismatch: note=%f func=%f", meanNote.Float64(), meanFunc.Float64())
+	}
+
+	const confidence = 0.95
+	quantNote, err := note.RequiredHashrateQuantile(seconds, confidence)
+	if err != nil {
+		t.Fatalf("note RequiredHashrateQuantile: %v", err)
+	}
+	quantFunc, err := RequiredHashrateQuantile(note, seconds, confidence)
+	if err != nil {
+		t.Fatalf("func RequiredHashrateQuantile: %v", err)
+	}
+	if !roughlyEqual(quantNote.Float64(), quantFunc.Float64()) {
+		t.Fatalf("quantile mismatch: note=%f func=%f", quantNote.Float64(), quantFunc.Float64())
+	}
+
+	generalNote, err := note.RequiredHashrate(seconds, WithMultiplier(2))
+	if err != nil {
+		t.Fatalf("note RequiredHashrate: %v", err)
+	}
+	generalFunc, err := RequiredHashrate(note, seconds, WithMultiplier(2))
+	if err != nil {
+		t.Fatalf("func RequiredHashrate: %v", err)
+	}
+	if !roughlyEqual(generalNote.Float64(), generalFunc.Float64()) {
+		t.Fatalf("general mismatch: note=%f func=%f", generalNote.Float64(), generalFunc.Float64())
+	}
+
+	measurement, err := note.RequiredHashrateMeasurement(seconds)
+	if err != nil {
+		t.Fatalf("note RequiredHashrateMeasurement: %v", err)
+	}
+	if !roughlyEqual(measurement.Float64(), meanFunc.Float64()) {
+		t.Fatalf("measurement mismatch: %f vs %f", measurement.Float64(), meanFunc.Float64())
+	}
+	if measurement.String() != measurement.Human().String() {
+		t.Fatalf("measurement String mismatch: %s vs %s", measurement.String(), measurement.Human())
+	}
+	customHuman := measurement.Human(WithHumanHashratePrecision(4))
+	expectedDisplay := fmt.Sprintf("%.4f %s", customHuman.Value, customHuman.Unit)
+	if customHuman.Display != expectedDisplay {
+		t.Fatalf("custom precision mismatch: got %s want %s", customHuman.Display, expectedDisplay)
+	}
+
+	meanMeasurement, err := note.RequiredHashrateMeanMeasurement(seconds)
+	if err != nil {
+		t.Fatalf("note RequiredHashrateMeanMeasurement: %v", err)
+	}
+	if !roughlyEqual(meanMeasurement.Float64(), meanFunc.Float64()) {
+		t.Fatalf("mean measurement mismatch: %f vs %f", meanMeasurement.Float64(), meanFunc.Float64())
+	}
+
+	quantMeasurement, err := note.RequiredHashrateQuantileMeasurement(seconds, confidence)
+	if err != nil {
+		t.Fatalf("note RequiredHashrateQuantileMeasurement: %v", err)
+	}
+	if !roughlyEqual(quantMeasurement.Float64(), quantFunc.Float64()) {
+		t.Fatalf("quant measurement mismatch: %f vs %f", quantMeasurement.Float64(), quantFunc.Float64())
+	}
+
+	scaledNote, err := note.Scale(1.5)
+	if err != nil {
+		t.Fatalf("note Scale: %v", err)
+	}
+	scaledFunc, err := ScaleNote(note, 1.5)
+	if err != nil {
+		t.Fatalf("func ScaleNote: %v", err)
+	}
+	if scaledNote.Label() != scaledFunc.Label() {
+		t.Fatalf("scale mismatch: note=%s func=%s", scaledNote.Label(), scaledFunc.Label())
+	}
+
+	combinedNote, err := note.CombineSerial("33Z53")
+	if err != nil {
+		t.Fatalf("note CombineSerial: %v", err)
+	}
+	combinedFunc, err := CombineNotesSerial(note, "33Z53")
+	if err != nil {
+		t.Fatalf("func CombineNotesSerial: %v", err)
+	}
+	if combinedNote.Label() != combinedFunc.Label() {
+		t.Fatalf("combine mismatch: note=%s func=%s", combinedNote.Label(), combinedFunc.Label())
+	}
+
+	diffNote, err := note.Difference("32Z00")
+	if err != nil {
+		t.Fatalf("note Difference: %v", err)
+	}
+	diffFunc, err := NoteDifference(note, "32Z00")
+	if err != nil {
+		t.Fatalf("func NoteDifference: %v", err)
+	}
+	if diffNote.Label() != diffFunc.Label() {
+		t.Fatalf("difference mismatch: note=%s func=%s", diffNote.Label(), diffFunc.Label())
+	}
+
+	targetNote, err := note.Target()
+	if err != nil {
+		t.Fatalf("note Target: %v", err)
+	}
+	targetFunc, err := TargetFor(note)
+	if err != nil {
+		t.Fatalf("func TargetFor: %v", err)
+	}
+	if targetNote.Cmp(targetFunc) != 0 {
+		t.Fatalf("target mismatch: note=%s func=%s", targetNote, targetFunc)
+	}
+
+	nbitsNote, err := note.NBits()
+	if err != nil {
+		t.Fatalf("note NBits: %v", err)
+	}
+	nbitsFunc, err := SharenoteToNBits(note)
+	if err != nil {
+		t.Fatalf("func SharenoteToNBits: %v", err)
+	}
+	if nbitsNote != nbitsFunc {
+		t.Fatalf("nbits mismatch: note=%s func=%s", nbitsNote, nbitsFunc)
+	}
+}
+
+func TestEnsureNote(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	resolved, err := EnsureNote(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Label() != "33Z53" {
+		t.Fatalf("ensure note mismatch: %s", resolved.Label())
+	}
+	resolved, err = EnsureNote(33.53)
+	if err != nil {
+		t.Fatalf("EnsureNote zbits: %v", err)
+	}
+	if resolved.Label() != "33Z53" {
+		t.Fatalf("unexpected label from zbits: %s", resolved.Label())
+	}
+	resolved, err = EnsureNote(33)
+	if err != nil {
+		t.Fatalf("EnsureNote integer: %v", err)
+	}
+	if resolved.Label() != "33Z00" {
+		t.Fatalf("unexpected label from integer zbits: %s", resolved.Label())
+	}
+	resolved, err = EnsureNote(uint32(1))
+	if err != nil {
+		t.Fatalf("EnsureNote uint32: %v", err)
+	}
+	if resolved.Label() != "1Z00" {
+		t.Fatalf("unexpected label from uint32 zbits: %s", resolved.Label())
+	}
+	if _, err := EnsureNote(-1.0); err == nil {
+		t.Fatal("expected error for negative zbits input")
+	}
+	if _, err := EnsureNote(int(-1)); err == nil {
+		t.Fatal("expected error for negative integer zbits input")
+	}
+	if _, err := EnsureNote(true); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+func TestEnsureNoteRawJSON(t *testing.T) {
+	for _, raw := range []json.RawMessage{
+		json.RawMessage(`"33Z53"`),
+		json.RawMessage(`33.53`),
+		json.RawMessage(` "33.53Z" `),
+	} {
+		note, err := EnsureNote(raw)
+		if err != nil {
+			t.Fatalf("EnsureNote(%s): %v", raw, err)
+		}
+		if note.Label() != "33Z53" {
+			t.Fatalf("EnsureNote(%s) = %s, want 33Z53", raw, note.Label())
+		}
+	}
+	for _, raw := range []json.RawMessage{
+		json.RawMessage(`{"z": 33}`),
+		json.RawMessage(`"bogus"`),
+		json.RawMessage(`-1`),
+		json.RawMessage(`1e20`),
+	} {
+		if _, err := EnsureNote(raw); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
+
+func TestNoteFromCentZDecimal(t *testing.T) {
+	cases := map[float64]string{33.53: "33Z53", 33.5: "33Z50", 33.529: "33Z53", 57: "57Z00"}
+	for input, want := range cases {
+		note, err := NoteFromCentZDecimal(input)
+		if err != nil {
+			t.Fatalf("%v: %v", input, err)
+		}
+		if note.Label() != want {
+			t.Fatalf("NoteFromCentZDecimal(%v) = %s, want %s", input, note.Label(), want)
+		}
+	}
+	if got := mustParseLabel("33Z05").CentZDecimal(); got != 33.05 {
+		t.Fatalf("CentZDecimal = %v, want 33.05", got)
+	}
+	if _, err := NoteFromCentZDecimal(-0.5); err == nil {
+		t.Fatal("expected error for negative input")
+	}
+}
+
+func TestNoteFromCentZBits(t *testing.T) {
+	note, err := NoteFromCentZBits(3353)
+	if err != nil {
+		t.Fatalf("NoteFromCentZBits: %v", err)
+	}
+	if note.Label() != "33Z53" {
+		t.Fatalf("unexpected label: %s", note.Label())
+	}
+	if note.Z != 33 || note.Cents != 53 {
+		t.Fatalf("unexpected components: %+v", note)
+	}
+	note = MustNoteFromCentZBits(centZUnitsPerZ + 1)
+	if note.Label() != "1Z01" {
+		t.Fatalf("unexpected label from MustNoteFromCentZBits: %s", note.Label())
+	}
+	if _, err := NoteFromCentZBits(-1); err == nil {
+		t.Fatal("expected error for negative cent-z input")
+	}
+}
+
+func TestTargetDeterministic(t *testing.T) {
+	note := mustParseLabel("57Z12")
+	target, err := TargetFor(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Sign() <= 0 {
+		t.Fatal("target should be positive")
+	}
+	// Validate monotonicity
+	next, err := TargetFor("57Z13")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Cmp(target) >= 0 {
+		t.Fatal("harder note should yield smaller target")
+	}
+
 	if FormatProbabilityDisplay(note.ZBits, 5) != "1 / 2^57.12000" {
 		t.Fatalf("unexpected probability display")
 	}
 }
 
-func TestHumaniseHashrate(t *testing.T) {
-	human := HumaniseHashrate(3.2e9)
-	if human.Unit != HashrateUnitGHps {
-		t.Fatalf("unexpected unit: %s", human.Unit)
+func TestHumaniseHashrate(t *testing.T) {
+	human := HumaniseHashrate(3.2e9)
+	if human.Unit != HashrateUnitGHps {
+		t.Fatalf("unexpected unit: %s", human.Unit)
+	}
+	if !strings.HasPrefix(human.Display, "3.20") {
+		t.Fatalf("unexpected display: %s", human.Display)
+	}
+}
+
+func TestStringers(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	if got := fmt.Sprint(note); got != "33Z53" {
+		t.Fatalf("unexpected note string: %s", got)
+	}
+
+	human := HumaniseHashrate(3.2e9)
+	if got := fmt.Sprint(human); got != human.Display {
+		t.Fatalf("unexpected hashrate string: %s", got)
+	}
+
+	estimate, err := EstimateNote(note, 5)
+	if err != nil {
+		t.Fatalf("EstimateNote: %v", err)
+	}
+	summary := fmt.Sprint(estimate)
+	for _, want := range []string{"BillEstimate{", "33Z53", "p=1 / 2^"} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("estimate string missing %q: %s", want, summary)
+		}
+	}
+
+	plan := SharenotePlan{
+		Sharenote:          note,
+		Bill:               estimate,
+		SecondsTarget:      5,
+		InputHashrateHPS:   estimate.RequiredHashratePrimary,
+		InputHashrateHuman: estimate.RequiredHashrateHuman,
+	}
+	planSummary := fmt.Sprint(plan)
+	for _, want := range []string{"SharenotePlan{", "33Z53", "5.00s"} {
+		if !strings.Contains(planSummary, want) {
+			t.Fatalf("plan string missing %q: %s", want, planSummary)
+		}
+	}
+}
+
+func TestEstimateNote(t *testing.T) {
+	estimate, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Label != "33Z53" {
+		t.Fatalf("unexpected label: %s", estimate.Label)
+	}
+	if !roughlyEqual(estimate.RequiredHashratePrimary, 7.431367665e9) {
+		t.Fatalf("primary mismatch: %f", estimate.RequiredHashratePrimary)
+	}
+	if estimate.RequiredHashrateHuman.Unit != HashrateUnitGHps {
+		t.Fatalf("unexpected human unit: %s", estimate.RequiredHashrateHuman.Unit)
+	}
+	if !strings.HasSuffix(estimate.RequiredHashrateHuman.Display, " GH/s") {
+		t.Fatalf("unexpected human display: %s", estimate.RequiredHashrateHuman.Display)
+	}
+	if !strings.HasPrefix(estimate.RequiredHashrateHuman.Display, "7.43") {
+		t.Fatalf("unexpected human display: %s", estimate.RequiredHashrateHuman.Display)
+	}
+}
+
+func TestEstimateConfidenceRejected(t *testing.T) {
+	for _, confidence := range []float64{0, 1, 1.5, math.NaN()} {
+		if _, err := EstimateNote("33Z53", 5, WithEstimateConfidence(confidence)); err == nil {
+			t.Fatalf("expected error for confidence %v", confidence)
+		}
+	}
+	if _, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.999)); err != nil {
+		t.Fatalf("unexpected error for valid confidence: %v", err)
+	}
+}
+
+func TestEstimator(t *testing.T) {
+	estimator, err := NewEstimator(5, WithEstimateConfidence(0.95), WithEstimateProbabilityPrecision(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := estimator.Note("33Z53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95), WithEstimateProbabilityPrecision(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() || got.RequiredHashratePrimary != want.RequiredHashratePrimary {
+		t.Fatalf("Estimator.Note = %s, want %s", got, want)
+	}
+	batch, err := estimator.Notes([]any{"33Z53", "34Z00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0].String() != want.String() {
+		t.Fatalf("unexpected batch: %v", batch)
+	}
+	if _, err := NewEstimator(0); err == nil {
+		t.Fatal("expected error for zero window")
+	}
+}
+
+func TestBillEstimatesCSVRoundTrip(t *testing.T) {
+	quantile, err := EstimateNotes([]any{"33Z53", 34.125}, 5, WithEstimateConfidence(0.95))
+	if err != nil {
+		t.Fatal(err)
 	}
-	if !strings.HasPrefix(human.Display, "3.20") {
-		t.Fatalf("unexpected display: %s", human.Display)
+	mean, err := EstimateNote("20Z00", 60, WithEstimateClampWindow(120))
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimates := append(quantile, mean)
+	var buf bytes.Buffer
+	if err := WriteBillEstimatesCSV(&buf, estimates); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadBillEstimatesCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(estimates) {
+		t.Fatalf("expected %d estimates, got %d", len(estimates), len(decoded))
+	}
+	for i, want := range estimates {
+		got := decoded[i]
+		if got.Label != want.Label || got.Sharenote.Label() != want.Sharenote.Label() || got.PrimaryMode != want.PrimaryMode {
+			t.Fatalf("row %d: got %s, want %s", i, got, want)
+		}
+		if !roughlyEqual(got.ZBits, want.ZBits) ||
+			!roughlyEqual(got.EffectiveSeconds, want.EffectiveSeconds) ||
+			!roughlyEqual(got.RequiredHashratePrimary, want.RequiredHashratePrimary) ||
+			!roughlyEqual(got.RequiredHashrateQuantile, want.RequiredHashrateQuantile) {
+			t.Fatalf("row %d numeric mismatch: got %+v, want %+v", i, got, want)
+		}
+		if got.RequiredHashrateHuman != want.RequiredHashrateHuman || got.ProbabilityDisplay != want.ProbabilityDisplay {
+			t.Fatalf("row %d display mismatch: got %+v, want %+v", i, got, want)
+		}
+		if (got.Quantile == nil) != (want.Quantile == nil) || (got.Quantile != nil && *got.Quantile != *want.Quantile) {
+			t.Fatalf("row %d quantile mismatch: got %v, want %v", i, got.Quantile, want.Quantile)
+		}
+	}
+
+	minimal, err := ReadBillEstimatesCSV(strings.NewReader("label,seconds_target\n33Z53,5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(minimal) != 1 || minimal[0].ZBits != 33.53 || minimal[0].EffectiveSeconds != 5 {
+		t.Fatalf("unexpected minimal estimate: %+v", minimal)
+	}
+	edited, err := ReadBillEstimatesCSV(strings.NewReader("label,zbits,seconds_target\n 33z53 ,33.53,5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited[0].Label != "33Z53" || edited[0].Label != edited[0].Sharenote.Label() {
+		t.Fatalf("expected canonical label, got %q (note %s)", edited[0].Label, edited[0].Sharenote.Label())
+	}
+	if _, err := ReadBillEstimatesCSV(strings.NewReader("zbits\n33.53\n")); err == nil {
+		t.Fatal("expected error for missing label column")
+	}
+	if _, err := ReadBillEstimatesCSV(strings.NewReader("label,zbits,seconds_target\n33Z53,34.1,5\n")); err == nil {
+		t.Fatal("expected error when zbits disagree with the label")
 	}
 }
 
-func TestStringers(t *testing.T) {
-	note := mustParseLabel("33Z53")
-	if got := fmt.Sprint(note); got != "33Z53" {
-		t.Fatalf("unexpected note string: %s", got)
+func TestWriteNoteHistogramPrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	counts := map[string]int{"33Z53": 12, "32Z00": 3, `bad"label\`: 1}
+	if err := WriteNoteHistogramPrometheus(&buf, counts, "sharenote_notes"); err != nil {
+		t.Fatal(err)
+	}
+	want := "# TYPE sharenote_notes gauge\n" +
+		"sharenote_notes{label=\"32Z00\"} 3\n" +
+		"sharenote_notes{label=\"33Z53\"} 12\n" +
+		"sharenote_notes{label=\"bad\\\"label\\\\\"} 1\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if err := WriteNoteHistogramPrometheus(&buf, counts, "bad-name"); err == nil {
+		t.Fatal("expected error for invalid metric name")
 	}
+}
 
-	human := HumaniseHashrate(3.2e9)
-	if got := fmt.Sprint(human); got != human.Display {
-		t.Fatalf("unexpected hashrate string: %s", got)
+func TestHashrateConfidenceRejected(t *testing.T) {
+	rig := HashrateValue{Value: 7.4314, Unit: HashrateUnitGHps}
+	for _, confidence := range []float64{0, 1, 1.5, math.NaN()} {
+		if _, err := NoteFromHashrate(rig, 5, WithConfidence(confidence)); err == nil {
+			t.Fatalf("NoteFromHashrate: expected error for confidence %v", confidence)
+		}
+		if _, err := RequiredHashrate("33Z53", 5, WithConfidence(confidence)); err == nil {
+			t.Fatalf("RequiredHashrate: expected error for confidence %v", confidence)
+		}
+		if _, err := HashrateRangeForNote("33Z53", 5, WithConfidence(confidence)); err == nil {
+			t.Fatalf("HashrateRangeForNote: expected error for confidence %v", confidence)
+		}
+		if _, err := PlanSharenoteFromHashrate(rig, 5, WithPlanConfidence(confidence)); err == nil {
+			t.Fatalf("PlanSharenoteFromHashrate: expected error for confidence %v", confidence)
+		}
 	}
+	if _, err := NoteFromHashrate(rig, 5, WithConfidence(0.95)); err != nil {
+		t.Fatalf("unexpected error for valid confidence: %v", err)
+	}
+}
 
-	estimate, err := EstimateNote(note, 5)
+func TestEstimateNoteHumanFields(t *testing.T) {
+	estimate, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95))
 	if err != nil {
-		t.Fatalf("EstimateNote: %v", err)
+		t.Fatal(err)
 	}
-	summary := fmt.Sprint(estimate)
-	for _, want := range []string{"BillEstimate{", "33Z53", "p=1 / 2^"} {
-		if !strings.Contains(summary, want) {
-			t.Fatalf("estimate string missing %q: %s", want, summary)
+	pairs := []struct {
+		name  string
+		value float64
+		human HumanHashrate
+	}{
+		{"primary", estimate.RequiredHashratePrimary, estimate.RequiredHashrateHuman},
+		{"mean", estimate.RequiredHashrateMean, estimate.RequiredHashrateMeanHuman},
+		{"quantile", estimate.RequiredHashrateQuantile, estimate.RequiredHashrateQuantileHuman},
+	}
+	for _, pair := range pairs {
+		want := HumaniseHashrate(pair.value)
+		if pair.human != want {
+			t.Fatalf("%s human mismatch: got %+v, want %+v", pair.name, pair.human, want)
 		}
 	}
+	if estimate.RequiredHashrateHuman != estimate.RequiredHashrateQuantileHuman {
+		t.Fatal("primary human should track the quantile when confidence is set")
+	}
+}
 
-	plan := SharenotePlan{
-		Sharenote:          note,
-		Bill:               estimate,
-		SecondsTarget:      5,
-		InputHashrateHPS:   estimate.RequiredHashratePrimary,
-		InputHashrateHuman: estimate.RequiredHashrateHuman,
+func TestBillEstimateSummary(t *testing.T) {
+	quantile, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95))
+	if err != nil {
+		t.Fatal(err)
 	}
-	planSummary := fmt.Sprint(plan)
-	for _, want := range []string{"SharenotePlan{", "33Z53", "5.00s"} {
-		if !strings.Contains(planSummary, want) {
-			t.Fatalf("plan string missing %q: %s", want, planSummary)
+	if got := quantile.Summary(); got != "Minting 33Z53 in 5s needs ~7.43 GH/s (95% confidence)." {
+		t.Fatalf("unexpected quantile summary: %q", got)
+	}
+	mean, err := EstimateNote("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("Minting 33Z53 in 5s needs ~%s (on average).", mean.RequiredHashrateHuman.Display)
+	if got := mean.Summary(); got != want {
+		t.Fatalf("unexpected mean summary: %q, want %q", got, want)
+	}
+	clamped, err := EstimateNote("33Z53", 1e-9, WithEstimateConfidence(0.95), WithEstimateClampWindow(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = fmt.Sprintf("Minting 33Z53 in 1s needs ~%s (95%% confidence).", clamped.RequiredHashrateHuman.Display)
+	if got := clamped.Summary(); got != want {
+		t.Fatalf("unexpected clamped summary: %q, want %q", got, want)
+	}
+}
+
+func TestEstimateRigHashrate(t *testing.T) {
+	slow, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95), WithEstimateRigHashrate(HashrateValue{Value: 5, Unit: HashrateUnitGHps}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slow.Feasible {
+		t.Fatal("5 GH/s rig should not meet the 95% requirement")
+	}
+	if !roughlyEqual(slow.Shortfall, slow.RequiredHashratePrimary-5e9) {
+		t.Fatalf("unexpected shortfall: %f", slow.Shortfall)
+	}
+	fast, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95), WithEstimateRigHashrate(HashrateValue{Value: 10, Unit: HashrateUnitGHps}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fast.Feasible || fast.Shortfall != 0 {
+		t.Fatalf("10 GH/s rig should be feasible: %+v", fast)
+	}
+	if _, err := EstimateNote("33Z53", 5, WithEstimateRigHashrate(HashrateValue{Value: -1})); err == nil {
+		t.Fatal("expected error for negative rig hashrate")
+	}
+}
+
+func TestEstimateHashratePrecision(t *testing.T) {
+	estimate, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95), WithEstimateHashratePrecision(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, human := range map[string]HumanHashrate{
+		"primary":  estimate.RequiredHashrateHuman,
+		"mean":     estimate.RequiredHashrateMeanHuman,
+		"quantile": estimate.RequiredHashrateQuantileHuman,
+	} {
+		want := fmt.Sprintf("%.4f %s", human.Value, human.Unit)
+		if human.Display != want {
+			t.Fatalf("%s display = %q, want %q", name, human.Display, want)
+		}
+	}
+	if estimate.RequiredHashrateHuman.Display != "7.4314 GH/s" {
+		t.Fatalf("unexpected primary display: %q", estimate.RequiredHashrateHuman.Display)
+	}
+}
+
+func TestEstimateReliabilityLabel(t *testing.T) {
+	estimate, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.ReliabilityLabel != "Often (95%)" {
+		t.Fatalf("unexpected reliability label: %q", estimate.ReliabilityLabel)
+	}
+	encoded, err := json.Marshal(estimate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"ReliabilityLabel":"Often (95%)"`) {
+		t.Fatalf("reliability label missing from JSON: %s", encoded)
+	}
+	if lvl := NearestReliabilityLevel(2.8); lvl.ID != ReliabilityOften95 {
+		t.Fatalf("NearestReliabilityLevel(2.8) = %s", lvl.ID)
+	}
+	if lvl := NearestReliabilityLevel(1); lvl.ID != ReliabilityMean {
+		t.Fatalf("NearestReliabilityLevel(1) = %s", lvl.ID)
+	}
+}
+
+func TestEstimateAnnotations(t *testing.T) {
+	annotations := map[string]string{"sku": "rig-s19"}
+	estimate, err := EstimateNote("33Z53", 5, WithEstimateAnnotations(annotations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations["sku"] = "mutated"
+	if estimate.Annotations["sku"] != "rig-s19" {
+		t.Fatalf("annotations not copied: %v", estimate.Annotations)
+	}
+	encoded, err := json.Marshal(estimate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"Annotations":{"sku":"rig-s19"}`) {
+		t.Fatalf("annotations missing from JSON: %s", encoded)
+	}
+	plain, err := EstimateNote("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err = json.Marshal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encoded), "Annotations") {
+		t.Fatalf("empty annotations should be omitted: %s", encoded)
+	}
+}
+
+func TestEstimateClampWindow(t *testing.T) {
+	clamped, err := EstimateNote("33Z53", 1e-9, WithEstimateClampWindow(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clamped.SecondsTarget != 1e-9 || clamped.EffectiveSeconds != 1 {
+		t.Fatalf("unexpected windows: target=%g effective=%g", clamped.SecondsTarget, clamped.EffectiveSeconds)
+	}
+	oneSecond, err := EstimateNote("33Z53", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(clamped.RequiredHashratePrimary, oneSecond.RequiredHashratePrimary) {
+		t.Fatalf("clamped primary mismatch: %f vs %f", clamped.RequiredHashratePrimary, oneSecond.RequiredHashratePrimary)
+	}
+
+	unclamped, err := EstimateNote("33Z53", 5, WithEstimateClampWindow(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unclamped.EffectiveSeconds != 5 {
+		t.Fatalf("expected window above floor to pass through, got %g", unclamped.EffectiveSeconds)
+	}
+}
+
+func TestBillEstimateMeetsHashrate(t *testing.T) {
+	estimate, err := EstimateNote("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	required := estimate.RequiredHashratePrimary
+	cases := []struct {
+		hashrate  float64
+		meets     bool
+		shortfall float64
+	}{
+		{required * 0.5, false, required * 0.5},
+		{required, true, 0},
+		{required * 2, true, 0},
+	}
+	for _, tc := range cases {
+		if got := estimate.MeetsHashrate(tc.hashrate); got != tc.meets {
+			t.Fatalf("MeetsHashrate(%f) = %v, want %v", tc.hashrate, got, tc.meets)
+		}
+		got := estimate.HashrateShortfall(tc.hashrate)
+		if tc.shortfall == 0 {
+			if got != 0 {
+				t.Fatalf("HashrateShortfall(%f) = %f, want 0", tc.hashrate, got)
+			}
+		} else if !roughlyEqual(got, tc.shortfall) {
+			t.Fatalf("HashrateShortfall(%f) = %f, want %f", tc.hashrate, got, tc.shortfall)
+		}
+	}
+}
+
+func TestEstimateUniqueNotes(t *testing.T) {
+	rows, err := EstimateUniqueNotes([]any{"33Z53", "30Z00", "33.53Z", "33Z 53CZ", 30.0}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 unique estimates, got %d", len(rows))
+	}
+	if rows[0].Label != "33Z53" || rows[1].Label != "30Z00" {
+		t.Fatalf("unexpected order: %s, %s", rows[0].Label, rows[1].Label)
+	}
+	if _, err := EstimateUniqueNotes([]any{"33Z53", "bogus"}, 5); err == nil {
+		t.Fatal("expected error for invalid note")
+	}
+}
+
+func TestStreamBillEstimatesJSON(t *testing.T) {
+	var buf strings.Builder
+	notes := []any{"33Z53", "30Z00", 20.5}
+	if err := StreamBillEstimatesJSON(&buf, notes, 5, WithEstimateConfidence(0.95)); err != nil {
+		t.Fatalf("StreamBillEstimatesJSON: %v", err)
+	}
+	var decoded []BillEstimate
+	if err := json.Unmarshal([]byte(buf.String()), &decoded); err != nil {
+		t.Fatalf("decode stream: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != len(notes) {
+		t.Fatalf("expected %d estimates, got %d", len(notes), len(decoded))
+	}
+	for i, want := range []string{"33Z53", "30Z00", "20Z50"} {
+		if decoded[i].Label != want {
+			t.Fatalf("index %d: got %s want %s", i, decoded[i].Label, want)
+		}
+	}
+	if !roughlyEqual(decoded[0].RequiredHashratePrimary, 7.431367665e9) {
+		t.Fatalf("primary mismatch: %f", decoded[0].RequiredHashratePrimary)
+	}
+
+	buf.Reset()
+	err := StreamBillEstimatesJSON(&buf, []any{"33Z53", "bogus"}, 5)
+	if err == nil {
+		t.Fatal("expected error for invalid note")
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &decoded); err != nil {
+		t.Fatalf("expected valid JSON after error: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 estimate before the error, got %d", len(decoded))
+	}
+}
+
+func TestSetDefaultWindow(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	if _, err := note.RequiredHashrateDefault(); err == nil {
+		t.Fatal("expected error without a default window")
+	}
+	if _, err := note.EstimateDefault(); err == nil {
+		t.Fatal("expected error without a default window")
+	}
+	if err := SetDefaultWindow(600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := SetDefaultWindow(0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	got, err := note.RequiredHashrateDefault(WithReliability(ReliabilityOften95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := note.RequiredHashrate(600, WithReliability(ReliabilityOften95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != want.Value {
+		t.Fatalf("RequiredHashrateDefault = %f, want %f", got.Value, want.Value)
+	}
+	mean, err := note.RequiredHashrateMeanDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantMean, _ := note.RequiredHashrateMean(600); mean.Value != wantMean.Value {
+		t.Fatalf("RequiredHashrateMeanDefault = %f, want %f", mean.Value, wantMean.Value)
+	}
+	quantile, err := note.RequiredHashrateQuantileDefault(0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(quantile.Value, want.Value) {
+		t.Fatalf("RequiredHashrateQuantileDefault = %f, want %f", quantile.Value, want.Value)
+	}
+	hashrateRange, err := note.HashrateRangeDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantRange, _ := note.HashrateRange(600); hashrateRange != wantRange {
+		t.Fatalf("HashrateRangeDefault = %+v, want %+v", hashrateRange, wantRange)
+	}
+	estimate, err := note.EstimateDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.SecondsTarget != 600 {
+		t.Fatalf("EstimateDefault window = %f, want 600", estimate.SecondsTarget)
+	}
+
+	for _, seconds := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if err := SetDefaultWindow(seconds); err == nil {
+			t.Fatalf("expected error for window %v", seconds)
+		}
+	}
+	if estimate, err := note.EstimateDefault(); err != nil || estimate.SecondsTarget != 600 {
+		t.Fatalf("rejected window should leave the default in place: %v", err)
+	}
+}
+
+func TestSetDefaultReliability(t *testing.T) {
+	if err := SetDefaultReliability(ReliabilityOften95); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := SetDefaultReliability(ReliabilityMean); err != nil {
+			t.Fatal(err)
 		}
+	})
+
+	estimate, err := EstimateNote("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.PrimaryMode != PrimaryModeQuantile || estimate.Quantile == nil || *estimate.Quantile != 0.95 {
+		t.Fatalf("expected default 95%% reliability, got %+v", estimate)
+	}
+	if !roughlyEqual(estimate.RequiredHashratePrimary, 7.431367665e9) {
+		t.Fatalf("primary mismatch: %f", estimate.RequiredHashratePrimary)
+	}
+	if !roughlyEqual(estimate.RequiredHashrateMean, 2.480651469e9) {
+		t.Fatalf("mean should ignore the default: %f", estimate.RequiredHashrateMean)
+	}
+
+	rate, err := RequiredHashrate("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(rate.Float64(), 7.431367665e9) {
+		t.Fatalf("RequiredHashrate ignored default: %f", rate.Float64())
+	}
+
+	explicit, err := EstimateNote("33Z53", 5, WithEstimateMultiplier(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(explicit.RequiredHashratePrimary, 2.480651469e9) {
+		t.Fatalf("explicit option should win: %f", explicit.RequiredHashratePrimary)
+	}
+
+	meanRange, err := HashrateRangeForNote("33Z53", 5, WithMultiplier(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainRange, err := HashrateRangeForNote("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plainRange != meanRange {
+		t.Fatalf("HashrateRangeForNote should keep the mean default: %+v vs %+v", plainRange, meanRange)
 	}
-}
 
-func TestEstimateNote(t *testing.T) {
-	estimate, err := EstimateNote("33Z53", 5, WithEstimateConfidence(0.95))
+	plan, err := PlanSharenoteFromHashrate(HashrateValue{Value: 7.4314, Unit: HashrateUnitGHps}, 5)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if estimate.Label != "33Z53" {
-		t.Fatalf("unexpected label: %s", estimate.Label)
+	if !roughlyEqual(plan.HashrateMultiplier, plan.Bill.Multiplier) || !roughlyEqual(plan.HashrateMultiplier, -math.Log(0.05)) {
+		t.Fatalf("plan halves disagree: hashrate ×%f, bill ×%f", plan.HashrateMultiplier, plan.Bill.Multiplier)
 	}
-	if !roughlyEqual(estimate.RequiredHashratePrimary, 7.431367665e9) {
-		t.Fatalf("primary mismatch: %f", estimate.RequiredHashratePrimary)
+	if plan.Sharenote.Label() != "33Z53" {
+		t.Fatalf("plan should pick the note at the default reliability, got %s", plan.Sharenote.Label())
 	}
-	if estimate.RequiredHashrateHuman.Unit != HashrateUnitGHps {
-		t.Fatalf("unexpected human unit: %s", estimate.RequiredHashrateHuman.Unit)
+	if err := plan.Verify(); err != nil {
+		t.Fatal(err)
 	}
-	if !strings.HasSuffix(estimate.RequiredHashrateHuman.Display, " GH/s") {
-		t.Fatalf("unexpected human display: %s", estimate.RequiredHashrateHuman.Display)
+
+	if err := SetDefaultReliability("bogus"); err == nil {
+		t.Fatal("expected error for unknown reliability")
 	}
-	if !strings.HasPrefix(estimate.RequiredHashrateHuman.Display, "7.43") {
-		t.Fatalf("unexpected human display: %s", estimate.RequiredHashrateHuman.Display)
+	if estimate, err := EstimateNote("33Z53", 5); err != nil || estimate.Quantile == nil || *estimate.Quantile != 0.95 {
+		t.Fatalf("rejected id should leave the default in place: %+v, %v", estimate, err)
 	}
 }
 
@@ -655,6 +2602,68 @@ func TestPlanSharenoteFromHashrate(t *testing.T) {
 	}
 }
 
+func TestSharenotePlanEncode(t *testing.T) {
+	plan, err := PlanSharenoteFromHashrate(
+		HashrateValue{Value: 7, Unit: HashrateUnitGHps},
+		5,
+		WithPlanHashrateOptions(WithReliability(ReliabilityOften95), WithNoteRounding(RoundingNearest)),
+		WithPlanEstimateOptions(WithEstimateConfidence(0.95)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := plan.Encode()
+	if strings.ContainsAny(token, "+/=") {
+		t.Fatalf("token is not base64url: %s", token)
+	}
+	decoded, err := DecodePlan(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Sharenote != plan.Sharenote || decoded.SecondsTarget != plan.SecondsTarget ||
+		decoded.InputHashrateHPS != plan.InputHashrateHPS || decoded.HashrateMultiplier != plan.HashrateMultiplier ||
+		decoded.Rounding != plan.Rounding {
+		t.Fatalf("decoded plan %+v does not match %+v", decoded, plan)
+	}
+	if decoded.Bill.PrimaryMode != plan.Bill.PrimaryMode || decoded.Bill.RequiredHashratePrimary != plan.Bill.RequiredHashratePrimary {
+		t.Fatalf("decoded bill %s does not match %s", decoded.Bill, plan.Bill)
+	}
+	if decoded.Bill.Quantile == nil || *decoded.Bill.Quantile != 0.95 {
+		t.Fatalf("decoded quantile mismatch: %v", decoded.Bill.Quantile)
+	}
+	for _, bad := range []string{"", "!!!", token[:len(token)-2]} {
+		if _, err := DecodePlan(bad); err == nil {
+			t.Fatalf("expected error decoding %q", bad)
+		}
+	}
+}
+
+func TestSharenotePlanVerify(t *testing.T) {
+	rig := HashrateValue{Value: 5, Unit: HashrateUnitGHps}
+	for _, opts := range [][]PlanOption{
+		nil,
+		{WithPlanReliability(ReliabilityOften95)},
+		{WithPlanHashrateOptions(WithNoteRounding(RoundingNearest))},
+	} {
+		plan, err := PlanSharenoteFromHashrate(rig, 5, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := plan.Verify(); err != nil {
+			t.Fatalf("valid plan %s failed verification: %v", plan, err)
+		}
+	}
+
+	plan, err := PlanSharenoteFromHashrate(rig, 5, WithPlanReliability(ReliabilityOften95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.InputHashrateHPS *= 2
+	if err := plan.Verify(); err == nil {
+		t.Fatal("expected corrupted plan to fail verification")
+	}
+}
+
 func TestArithmeticHelpers(t *testing.T) {
 	noteA := mustParseLabel("33Z53")
 	noteB := mustParseLabel("20Z10")
@@ -705,6 +2714,488 @@ func TestArithmeticHelpers(t *testing.T) {
 	}
 }
 
+func TestTotalExpectedHashes(t *testing.T) {
+	total, err := TotalExpectedHashes([]any{"20Z00", "21Z00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(total.Value, math.Exp2(20)+math.Exp2(21)) {
+		t.Fatalf("TotalExpectedHashes = %f", total.Value)
+	}
+	if _, err := TotalExpectedHashes([]any{1023.9, 1023.9}); err == nil {
+		t.Fatal("expected overflow error")
+	}
+	if _, err := TotalExpectedHashes(nil); err == nil {
+		t.Fatal("expected error for no notes")
+	}
+}
+
+func TestWorkAccumulator(t *testing.T) {
+	var acc WorkAccumulator
+	if _, err := acc.AsNote(); err == nil {
+		t.Fatal("expected error for empty accumulator")
+	}
+	notes := []any{"30Z00", "31Z50", 32.25}
+	for _, note := range notes {
+		if err := acc.Add(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := CombineNotesSerial(notes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := acc.AsNote()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Label() != want.Label() || !roughlyEqual(got.ZBits, want.ZBits) {
+		t.Fatalf("accumulator = %s (%f), want %s (%f)", got, got.ZBits, want, want.ZBits)
+	}
+	if !roughlyEqual(acc.TotalDifficulty(), math.Exp2(want.ZBits)) {
+		t.Fatalf("total difficulty mismatch: %f", acc.TotalDifficulty())
+	}
+	if err := acc.Add("bogus"); err == nil {
+		t.Fatal("expected error for invalid note")
+	}
+}
+
+func TestInterpolatedHashrate(t *testing.T) {
+	got, err := InterpolatedHashrate("33Z53", 5, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := RequiredHashrateQuantile("33Z53", 5, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(got.Value, want.Value) {
+		t.Fatalf("InterpolatedHashrate = %f, want %f", got.Value, want.Value)
+	}
+	mid, err := InterpolatedHashrate("33Z53", 5, 0.97)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mid.Value <= want.Value {
+		t.Fatalf("expected 97%% to exceed 95%%: %f vs %f", mid.Value, want.Value)
+	}
+	for _, confidence := range []float64{0, 1, -0.5, math.NaN()} {
+		if _, err := InterpolatedHashrate("33Z53", 5, confidence); err == nil {
+			t.Fatalf("expected error for confidence %v", confidence)
+		}
+	}
+}
+
+func TestNoteFromHashBudget(t *testing.T) {
+	const budget = 5e10
+	note, err := NoteFromHashBudget(budget, 10, WithMultiplier(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	needed := math.Exp2(note.ZBits) * 3
+	if needed > budget*(1+1e-9) || needed < budget*math.Exp2(-CentZBitStep) {
+		t.Fatalf("expected hashes %f not within one cent-Z of budget %f", needed, budget)
+	}
+	if _, err := NoteFromHashBudget(0, 10); err == nil {
+		t.Fatal("expected error for zero hashes")
+	}
+	if _, err := NoteFromHashBudget(budget, 0); err == nil {
+		t.Fatal("expected error for zero seconds")
+	}
+}
+
+func TestEmpiricalNote(t *testing.T) {
+	note, err := EmpiricalNote(1e10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Label() != "33Z21" || !roughlyEqual(note.ZBits, math.Log2(1e10)) {
+		t.Fatalf("unexpected note %s (%f)", note.Label(), note.ZBits)
+	}
+	halved, err := EmpiricalNote(2e10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(halved.ZBits, note.ZBits) {
+		t.Fatalf("2e10/2 should match 1e10/1: %f vs %f", halved.ZBits, note.ZBits)
+	}
+	if _, err := EmpiricalNote(1e10, 0); err == nil {
+		t.Fatal("expected error for zero successes")
+	}
+	if _, err := EmpiricalNote(0, 1); err == nil {
+		t.Fatal("expected error for zero hashes")
+	}
+}
+
+func TestReliabilityLevelString(t *testing.T) {
+	mean, err := getReliabilityLevel(ReliabilityMean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mean.String(); got != "mean (On average, ×1.000)" {
+		t.Fatalf("mean String = %q", got)
+	}
+	often, err := getReliabilityLevel(ReliabilityOften95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := often.String(); got != "often_95 (Often (95%), ×2.996)" {
+		t.Fatalf("often String = %q", got)
+	}
+}
+
+func TestForEachReliabilityLevel(t *testing.T) {
+	t.Cleanup(func() {
+		customReliabilityMu.Lock()
+		customReliabilityLevels = nil
+		customReliabilityMu.Unlock()
+	})
+	if err := RegisterReliabilityLevel(ReliabilityLevel{ID: "paranoid", Label: "Paranoid", Multiplier: 12}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []ReliabilityID
+	ForEachReliabilityLevel(func(lvl ReliabilityLevel) bool {
+		ids = append(ids, lvl.ID)
+		return true
+	})
+	levels := ReliabilityLevels()
+	if len(ids) != len(levels) {
+		t.Fatalf("visited %d levels, want %d", len(ids), len(levels))
+	}
+	for i, lvl := range levels {
+		if ids[i] != lvl.ID {
+			t.Fatalf("position %d: got %s, want %s", i, ids[i], lvl.ID)
+		}
+	}
+	if ids[len(ids)-1] != "paranoid" {
+		t.Fatalf("custom level not visited last: %v", ids)
+	}
+
+	visited := 0
+	ForEachReliabilityLevel(func(lvl ReliabilityLevel) bool {
+		visited++
+		return lvl.ID != ReliabilityUsually90
+	})
+	if visited != 2 {
+		t.Fatalf("expected early stop after 2 levels, visited %d", visited)
+	}
+
+	var during []ReliabilityID
+	ForEachReliabilityLevel(func(lvl ReliabilityLevel) bool {
+		if lvl.ID == ReliabilityMean {
+			if err := RegisterReliabilityLevel(ReliabilityLevel{ID: "relaxed", Label: "Relaxed", Multiplier: 0.5}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		during = append(during, lvl.ID)
+		return true
+	})
+	if !slices.Equal(during, ids) {
+		t.Fatalf("registration during iteration changed the visited levels: %v, want %v", during, ids)
+	}
+}
+
+func TestRegisterReliabilityLevelOrdering(t *testing.T) {
+	t.Cleanup(func() {
+		customReliabilityMu.Lock()
+		customReliabilityLevels = nil
+		customReliabilityMu.Unlock()
+	})
+	if err := RegisterReliabilityLevel(ReliabilityLevel{ID: "paranoid", Label: "Paranoid", Multiplier: 12}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterReliabilityLevel(ReliabilityLevel{ID: "relaxed", Label: "Relaxed", Multiplier: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	want := []ReliabilityID{
+		ReliabilityMean, ReliabilityUsually90, ReliabilityOften95, ReliabilityVeryLikely99, ReliabilityAlmost999,
+		"relaxed", "paranoid",
+	}
+	for range 3 {
+		levels := ReliabilityLevels()
+		options := ReliabilityOptions()
+		if len(levels) != len(want) || len(options) != len(want) {
+			t.Fatalf("unexpected level count: %d levels, %d options", len(levels), len(options))
+		}
+		for i, id := range want {
+			if levels[i].ID != id || options[i].ID != id {
+				t.Fatalf("position %d: got %s/%s, want %s", i, levels[i].ID, options[i].ID, id)
+			}
+		}
+	}
+	hashrate, err := RequiredHashrate("33Z53", 5, WithReliability("paranoid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mean, err := RequiredHashrateMean("33Z53", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(hashrate.Value, mean.Value*12) {
+		t.Fatalf("custom multiplier not applied: %f", hashrate.Value)
+	}
+	if err := RegisterReliabilityLevel(ReliabilityLevel{ID: ReliabilityOften95, Multiplier: 3}); err == nil {
+		t.Fatal("expected error for duplicate id")
+	}
+	if err := RegisterReliabilityLevel(ReliabilityLevel{ID: "broken", Multiplier: 0}); err == nil {
+		t.Fatal("expected error for non-positive multiplier")
+	}
+}
+
+func TestRegisterReliabilityLevelConcurrentDuplicate(t *testing.T) {
+	t.Cleanup(func() {
+		customReliabilityMu.Lock()
+		customReliabilityLevels = nil
+		customReliabilityMu.Unlock()
+	})
+	const workers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- RegisterReliabilityLevel(ReliabilityLevel{ID: "shared", Multiplier: 4})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one successful registration, got %d", succeeded)
+	}
+	customReliabilityMu.RLock()
+	defer customReliabilityMu.RUnlock()
+	if len(customReliabilityLevels) != 1 {
+		t.Fatalf("expected one registered level, got %d", len(customReliabilityLevels))
+	}
+}
+
+func TestMultiplierTable(t *testing.T) {
+	table, err := MultiplierTable([]float64{0.5, 0.9, 0.99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{math.Ln2, math.Log(10), math.Log(100)}
+	for i, entry := range table {
+		if !roughlyEqual(entry.Multiplier, want[i]) {
+			t.Fatalf("entry %d: multiplier %f, want %f", i, entry.Multiplier, want[i])
+		}
+	}
+	if !roughlyEqual(table[1].Multiplier, 2.302585093) {
+		t.Fatalf("unexpected 90%% multiplier: %f", table[1].Multiplier)
+	}
+	if _, err := MultiplierTable([]float64{0.5, 1}); err == nil {
+		t.Fatal("expected error for confidence 1")
+	}
+}
+
+func TestReliabilityOptions(t *testing.T) {
+	options := ReliabilityOptions()
+	if len(options) != len(ReliabilityLevels()) {
+		t.Fatalf("unexpected option count: %d", len(options))
+	}
+	if options[0].ID != ReliabilityMean || options[0].Confidence != nil {
+		t.Fatalf("expected mean first with nil confidence, got %+v", options[0])
+	}
+	encoded, err := json.Marshal(options[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"id":"mean","label":"On average","multiplier":1,"confidence":null}` {
+		t.Fatalf("unexpected JSON: %s", encoded)
+	}
+	if options[2].Confidence == nil || *options[2].Confidence != 0.95 {
+		t.Fatalf("unexpected often_95 option: %+v", options[2])
+	}
+}
+
+func TestFormatProbabilityDisplayTrim(t *testing.T) {
+	cases := []struct {
+		zbits     float64
+		precision int
+		want      string
+	}{
+		{33.53, 8, "1 / 2^33.53"},
+		{33, 8, "1 / 2^33"},
+		{40, 0, "1 / 2^40"},
+		{33.123456789, 4, "1 / 2^33.1235"},
+	}
+	for _, tc := range cases {
+		if got := FormatProbabilityDisplayTrim(tc.zbits, tc.precision); got != tc.want {
+			t.Fatalf("FormatProbabilityDisplayTrim(%v, %d) = %q, want %q", tc.zbits, tc.precision, got, tc.want)
+		}
+	}
+	if got := FormatProbabilityDisplay(33.53, 8); got != "1 / 2^33.53000000" {
+		t.Fatalf("FormatProbabilityDisplay changed: %q", got)
+	}
+}
+
+func TestDivideNotesExact(t *testing.T) {
+	for _, pair := range [][2]string{{"34Z00", "33Z00"}, {"33Z53", "20Z17"}, {"57Z12", "1Z00"}} {
+		exact, err := DivideNotesExact(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		approx, err := DivideNotes(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := exact.Float64()
+		if !roughlyEqual(got, approx) {
+			t.Fatalf("DivideNotesExact(%s, %s) = %g, DivideNotes = %g", pair[0], pair[1], got, approx)
+		}
+	}
+	exact, err := DivideNotesExact("34Z00", "33Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact.Cmp(big.NewRat(2, 1)) != 0 {
+		t.Fatalf("expected exactly 2, got %s", exact)
+	}
+}
+
+func TestEquivalentCount(t *testing.T) {
+	count, err := EquivalentCount("34Z00", "33Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(count, 2) {
+		t.Fatalf("EquivalentCount = %f, want 2", count)
+	}
+	if _, err := EquivalentCount(2000.0, 0.0); err == nil {
+		t.Fatal("expected error for non-finite count")
+	}
+}
+
+func TestSplitNoteSerial(t *testing.T) {
+	for _, parts := range []int{1, 3, 8} {
+		copies := make([]any, parts)
+		for i := range copies {
+			copies[i] = "33Z53"
+		}
+		combined, err := CombineNotesSerial(copies...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		split, err := SplitNoteSerial(combined, parts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if split.Label() != "33Z53" {
+			t.Fatalf("split of %d parts = %s, want 33Z53", parts, split)
+		}
+	}
+	if _, err := SplitNoteSerial("33Z53", 0); err == nil {
+		t.Fatal("expected error for zero parts")
+	}
+	if _, err := SplitNoteSerial("1Z00", 4); err == nil {
+		t.Fatal("expected error for negative result")
+	}
+}
+
+func TestCombineNotesSerialBreakdown(t *testing.T) {
+	combined, shares, err := CombineNotesSerialBreakdown("33Z00", "33Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combined.Label() != "34Z00" {
+		t.Fatalf("unexpected combined note: %s", combined)
+	}
+	if len(shares) != 2 || !roughlyEqual(shares[0], 0.5) || !roughlyEqual(shares[1], 0.5) {
+		t.Fatalf("unexpected shares: %v", shares)
+	}
+	_, shares, err = CombineNotesSerialBreakdown("30Z00", "32Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !roughlyEqual(shares[0], 0.2) || !roughlyEqual(shares[1], 0.8) {
+		t.Fatalf("unexpected weighted shares: %v", shares)
+	}
+	if _, _, err := CombineNotesSerialBreakdown(); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
+func TestTargetHex(t *testing.T) {
+	note := mustParseLabel("33Z53")
+	fromMethod, err := note.TargetHex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromFunc, err := SharenoteToTargetHex("33Z53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromMethod != fromFunc {
+		t.Fatalf("method %s != function %s", fromMethod, fromFunc)
+	}
+	if len(fromMethod) != 64 {
+		t.Fatalf("expected 64 hex chars, got %d", len(fromMethod))
+	}
+	target, err := TargetFor(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, ok := new(big.Int).SetString(fromMethod, 16)
+	if !ok || parsed.Cmp(target) != 0 {
+		t.Fatalf("hex %s does not decode to target %s", fromMethod, target)
+	}
+	whole, err := SharenoteToTargetHex("32Z00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if whole != strings.Repeat("0", 7)+"1"+strings.Repeat("0", 56) {
+		t.Fatalf("unexpected 32Z00 target: %s", whole)
+	}
+	if _, err := SharenoteToTargetHex("0Z00"); err == nil {
+		t.Fatal("expected error for target overflowing 256 bits")
+	}
+}
+
+func TestCombinedTargetSerial(t *testing.T) {
+	target, err := CombinedTargetSerial("33Z53", "20Z10", "33Z54")
+	if err != nil {
+		t.Fatal(err)
+	}
+	combined, err := CombineNotesSerial("33Z53", "20Z10", "33Z54")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := TargetFor(combined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Cmp(expected) != 0 {
+		t.Fatalf("target mismatch: %s vs %s", target, expected)
+	}
+	if _, err := CombinedTargetSerial(); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
+func TestMeanNote(t *testing.T) {
+	mean, err := MeanNote([]any{"32Z00", "34Z00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := math.Log2((math.Exp2(32) + math.Exp2(34)) / 2)
+	if !roughlyEqual(mean.ZBits, expected) {
+		t.Fatalf("unexpected mean zbits: got %f want %f", mean.ZBits, expected)
+	}
+	if mean.Z != 33 {
+		t.Fatalf("expected mean in the 33Z band, got %s", mean.Label())
+	}
+	if _, err := MeanNote(nil); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
 func TestArithmeticVectorsFromJSON(t *testing.T) {
 	_, file, _, ok := runtime.Caller(0)
 	if !ok {
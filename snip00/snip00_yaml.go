@@ -0,0 +1,27 @@
+//go:build yaml
+
+package snip00
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements yaml.Marshaler by emitting the canonical label as a plain scalar.
+func (n Sharenote) MarshalYAML() (any, error) {
+	return n.Label(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler by parsing a scalar label (e.g. "33Z53").
+func (n *Sharenote) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("sharenote yaml must be a scalar label, got kind %d", value.Kind)
+	}
+	note, err := parseLabel(value.Value)
+	if err != nil {
+		return err
+	}
+	*n = note
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build yaml
+
+package snip00
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSharenoteYAMLRoundTrip(t *testing.T) {
+	type config struct {
+		Note Sharenote `yaml:"note"`
+	}
+	data, err := yaml.Marshal(config{Note: mustParseLabel("33Z53")})
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "note: 33Z53" {
+		t.Fatalf("unexpected yaml: %q", data)
+	}
+	var decoded config
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("yaml.Unmarshal: %v", err)
+	}
+	if decoded.Note.Label() != "33Z53" {
+		t.Fatalf("unexpected decoded label: %s", decoded.Note.Label())
+	}
+	if err := yaml.Unmarshal([]byte("note: [1, 2]"), &decoded); err == nil {
+		t.Fatal("expected error for non-scalar note")
+	}
+}